@@ -0,0 +1,231 @@
+package names2stats
+
+import (
+	"errors"
+	"io/fs"
+	"iter"
+	"sync"
+	"time"
+)
+
+const (
+	AdaptiveMaxDefault       int           = 64
+	AdaptiveWindowDefault    int           = 32
+	AdaptiveTargetDefault    time.Duration = 10 * time.Millisecond
+	AdaptiveErrorRateDefault float64       = 0.05
+)
+
+type AdaptiveConcurrency struct {
+	Min          int
+	Max          int
+	Target       time.Duration
+	MaxErrorRate float64
+	Window       int
+	NoSlab       bool
+}
+
+func (a AdaptiveConcurrency) normalized() AdaptiveConcurrency {
+	a.Min = max(1, a.Min)
+	if a.Max <= 0 {
+		a.Max = AdaptiveMaxDefault
+	}
+	a.Max = max(a.Min, a.Max)
+	if a.Window <= 0 {
+		a.Window = AdaptiveWindowDefault
+	}
+	if a.Target <= 0 {
+		a.Target = AdaptiveTargetDefault
+	}
+	if a.MaxErrorRate <= 0 {
+		a.MaxErrorRate = AdaptiveErrorRateDefault
+	}
+	return a
+}
+
+func struggling(e error) bool {
+	switch {
+	case nil == e:
+		return false
+	case errors.Is(e, fs.ErrNotExist),
+		errors.Is(e, fs.ErrPermission),
+		errors.Is(e, ErrAlreadySeen),
+		errors.Is(e, ErrNotOwned):
+		return false
+	default:
+		return true
+	}
+}
+
+type aimd struct {
+	AdaptiveConcurrency
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    int
+	inflight int
+	stopped  bool
+
+	samples int
+	errors  int
+	elapsed time.Duration
+}
+
+func (a AdaptiveConcurrency) newAimd() *aimd {
+	var c *aimd = &aimd{AdaptiveConcurrency: a.normalized()}
+	c.cond = sync.NewCond(&c.mu)
+	c.limit = c.Min
+	return c
+}
+
+func (c *aimd) acquire() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for !c.stopped && c.limit <= c.inflight {
+		c.cond.Wait()
+	}
+	if c.stopped {
+		return false
+	}
+	c.inflight++
+	return true
+}
+
+func (c *aimd) release(took time.Duration, e error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.cond.Broadcast()
+
+	c.inflight--
+	c.samples++
+	c.elapsed += took
+	if struggling(e) {
+		c.errors++
+	}
+	if c.samples < c.Window {
+		return
+	}
+
+	var mean time.Duration = c.elapsed / time.Duration(c.samples)
+	var rate float64 = float64(c.errors) / float64(c.samples)
+	switch {
+	case c.Target < mean, c.MaxErrorRate < rate:
+		c.limit = max(c.Min, c.limit/2)
+	default:
+		c.limit = min(c.Max, c.limit+1)
+	}
+	c.samples, c.errors, c.elapsed = 0, 0, 0
+}
+
+func (c *aimd) stop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stopped = true
+	c.cond.Broadcast()
+}
+
+type statResult struct {
+	stat BasicStat
+	err  error
+}
+
+type statSlot struct {
+	statResult
+	ready chan struct{}
+}
+
+type statSlab struct{ free chan *statSlot }
+
+func newStatSlab(n int, disabled bool) statSlab {
+	if disabled {
+		return statSlab{}
+	}
+
+	var slots []statSlot = make([]statSlot, n)
+	var free chan *statSlot = make(chan *statSlot, n)
+	for i := range slots {
+		slots[i].ready = make(chan struct{}, 1)
+		free <- &slots[i]
+	}
+	return statSlab{free: free}
+}
+
+func (s statSlab) get(done <-chan struct{}) (*statSlot, bool) {
+	if nil == s.free {
+		return &statSlot{ready: make(chan struct{}, 1)}, true
+	}
+	select {
+	case slot := <-s.free:
+		return slot, true
+	case <-done:
+		return nil, false
+	}
+}
+
+func (s statSlab) put(slot *statSlot) {
+	if nil == s.free {
+		return
+	}
+	slot.statResult = statResult{}
+	s.free <- slot
+}
+
+func (a AdaptiveConcurrency) TaggedToBasicStats(
+	stat FilenameToBasicStat,
+	names TaggedNameIter,
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		var c *aimd = a.newAimd()
+		var pending chan *statSlot = make(chan *statSlot, c.Max)
+		var slab statSlab = newStatSlab(c.Max+2, a.NoSlab)
+		var done chan struct{} = make(chan struct{})
+		defer c.stop()
+		defer close(done)
+
+		go func() {
+			defer close(pending)
+			for tagged, e := range names {
+				slot, ok := slab.get(done)
+				if !ok {
+					return
+				}
+
+				if nil != e {
+					slot.statResult = statResult{err: e}
+					slot.ready <- struct{}{}
+					select {
+					case pending <- slot:
+					case <-done:
+					}
+					return
+				}
+
+				if !c.acquire() {
+					return
+				}
+				select {
+				case pending <- slot:
+				case <-done:
+					c.release(0, nil)
+					return
+				}
+
+				go func() {
+					var started time.Time = time.Now()
+					s, e := stat(tagged.Name)
+					c.release(time.Since(started), e)
+					slot.statResult = statResult{stat: s, err: tagged.Origin.WrapErr(e)}
+					slot.ready <- struct{}{}
+				}()
+			}
+		}()
+
+		for slot := range pending {
+			<-slot.ready
+			var r statResult = slot.statResult
+			slab.put(slot)
+			if !yield(r.stat, r.err) {
+				return
+			}
+		}
+	}
+}
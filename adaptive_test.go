@@ -0,0 +1,42 @@
+package names2stats
+
+import (
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func BenchmarkAdaptiveSlab(b *testing.B) {
+	var stat FilenameToBasicStat = func(name string) (BasicStat, error) {
+		return BasicStat{Path: name, Size: 1, FileType: FileTypeRglr}, nil
+	}
+
+	for _, noSlab := range []bool{false, true} {
+		b.Run("no_slab="+strconv.FormatBool(noSlab), func(b *testing.B) {
+			var a AdaptiveConcurrency = AdaptiveConcurrency{Min: 8, Max: 8, NoSlab: noSlab}
+			var names TaggedNameIter = func(yield func(TaggedName, error) bool) {
+				for range b.N {
+					if !yield(TaggedName{Name: "var/log/app/access.log"}, nil) {
+						return
+					}
+				}
+			}
+
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for _, e := range a.TaggedToBasicStats(stat, names) {
+				if nil != e {
+					b.Fatal(e)
+				}
+			}
+
+			b.StopTimer()
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(after.NumGC-before.NumGC)*1e6/float64(b.N), "gcs/Mrecords")
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package names2stats
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"iter"
+)
+
+const ArrowBatchRowsDefault int = 64 * 1024
+
+const arrowFileMagic string = "ARROW1"
+
+const (
+	arrowMetadataV5 int16 = 4
+
+	arrowHeaderSchema      byte = 1
+	arrowHeaderRecordBatch byte = 3
+
+	arrowTypeInt       byte = 2
+	arrowTypeUtf8      byte = 5
+	arrowTypeTimestamp byte = 10
+
+	arrowTimeUnitMicros int16 = 2
+)
+
+type fbSlot struct {
+	ID    int
+	Value any
+}
+
+type fbTable []fbSlot
+
+type fbStructs struct {
+	Count int
+	Data  []byte
+}
+
+type fbWriter struct{ buf []byte }
+
+func (w *fbWriter) pad(align int) {
+	for 0 != len(w.buf)%align {
+		w.buf = append(w.buf, 0)
+	}
+}
+
+func (w *fbWriter) patch(at int, target int) {
+	binary.LittleEndian.PutUint32(w.buf[at:], uint32(target-at))
+}
+
+func fbScalarSize(v any) int {
+	switch v.(type) {
+	case bool, byte:
+		return 1
+	case int16:
+		return 2
+	case int32:
+		return 4
+	case int64:
+		return 8
+	default:
+		return 0
+	}
+}
+
+func (w *fbWriter) scalar(v any) {
+	var size int = fbScalarSize(v)
+	w.pad(size)
+	switch s := v.(type) {
+	case bool:
+		var b byte
+		if s {
+			b = 1
+		}
+		w.buf = append(w.buf, b)
+	case byte:
+		w.buf = append(w.buf, s)
+	case int16:
+		w.buf = binary.LittleEndian.AppendUint16(w.buf, uint16(s))
+	case int32:
+		w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(s))
+	case int64:
+		w.buf = binary.LittleEndian.AppendUint64(w.buf, uint64(s))
+	}
+}
+
+type fbPending struct {
+	At    int
+	Value any
+}
+
+func (w *fbWriter) table(t fbTable) int {
+	var slots int
+	for _, s := range t {
+		slots = max(slots, s.ID+1)
+	}
+
+	w.pad(2)
+	var vtable int = len(w.buf)
+	w.buf = append(w.buf, make([]byte, 4+2*slots)...)
+
+	w.pad(8)
+	var start int = len(w.buf)
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(start-vtable))
+
+	var offsets []uint16 = make([]uint16, slots)
+	var children []fbPending
+	for _, s := range t {
+		var size int = fbScalarSize(s.Value)
+		switch size {
+		case 0:
+			w.pad(4)
+			children = append(children, fbPending{At: len(w.buf), Value: s.Value})
+			w.buf = append(w.buf, 0, 0, 0, 0)
+			offsets[s.ID] = uint16(len(w.buf) - 4 - start)
+		default:
+			w.scalar(s.Value)
+			offsets[s.ID] = uint16(len(w.buf) - size - start)
+		}
+	}
+
+	binary.LittleEndian.PutUint16(w.buf[vtable:], uint16(4+2*slots))
+	binary.LittleEndian.PutUint16(w.buf[vtable+2:], uint16(len(w.buf)-start))
+	for i, off := range offsets {
+		binary.LittleEndian.PutUint16(w.buf[vtable+4+2*i:], off)
+	}
+
+	for _, c := range children {
+		w.patch(c.At, w.value(c.Value))
+	}
+	return start
+}
+
+func (w *fbWriter) value(v any) int {
+	switch c := v.(type) {
+	case string:
+		w.pad(4)
+		var at int = len(w.buf)
+		w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(len(c)))
+		w.buf = append(append(w.buf, c...), 0)
+		return at
+	case fbTable:
+		return w.table(c)
+	case []fbTable:
+		w.pad(4)
+		var at int = len(w.buf)
+		w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(len(c)))
+		var slots int = len(w.buf)
+		w.buf = append(w.buf, make([]byte, 4*len(c))...)
+		for i, t := range c {
+			w.patch(slots+4*i, w.table(t))
+		}
+		return at
+	case fbStructs:
+		for 4 != len(w.buf)%8 {
+			w.buf = append(w.buf, 0)
+		}
+		var at int = len(w.buf)
+		w.buf = binary.LittleEndian.AppendUint32(w.buf, uint32(c.Count))
+		w.buf = append(w.buf, c.Data...)
+		return at
+	default:
+		panic("unsupported flatbuffer value")
+	}
+}
+
+func fbFinish(root fbTable) []byte {
+	var w fbWriter = fbWriter{buf: make([]byte, 4, 256)}
+	w.patch(0, w.table(root))
+	w.pad(8)
+	return w.buf
+}
+
+type arrowColumn struct {
+	Name     string
+	TypeType byte
+	Type     fbTable
+}
+
+var arrowColumns []arrowColumn = []arrowColumn{
+	{Name: "path", TypeType: arrowTypeUtf8, Type: fbTable{}},
+	{
+		Name:     "size",
+		TypeType: arrowTypeInt,
+		Type:     fbTable{{ID: 0, Value: int32(64)}, {ID: 1, Value: true}},
+	},
+	{
+		Name:     "modified_time",
+		TypeType: arrowTypeTimestamp,
+		Type: fbTable{
+			{ID: 0, Value: arrowTimeUnitMicros},
+			{ID: 1, Value: "UTC"},
+		},
+	},
+	{Name: "file_type", TypeType: arrowTypeUtf8, Type: fbTable{}},
+}
+
+func arrowSchema() fbTable {
+	var fields []fbTable
+	for _, c := range arrowColumns {
+		fields = append(fields, fbTable{
+			{ID: 0, Value: c.Name},
+			{ID: 1, Value: false},
+			{ID: 2, Value: c.TypeType},
+			{ID: 3, Value: c.Type},
+			{ID: 5, Value: []fbTable{}},
+		})
+	}
+	return fbTable{{ID: 1, Value: fields}}
+}
+
+func arrowMessage(headerType byte, header fbTable, bodyLength int64) []byte {
+	return fbFinish(fbTable{
+		{ID: 0, Value: arrowMetadataV5},
+		{ID: 1, Value: headerType},
+		{ID: 2, Value: header},
+		{ID: 3, Value: bodyLength},
+	})
+}
+
+type arrowBlock struct {
+	Offset     int64
+	MetaLength int32
+	BodyLength int64
+}
+
+type ArrowWriter struct {
+	BatchRows int
+	File      bool
+	FileTypes FileTypeToString
+
+	w       *bufio.Writer
+	offset  int64
+	started bool
+	rows    []BasicStat
+	blocks  []arrowBlock
+}
+
+func (c FileTypeToString) NewArrowWriter(wtr io.Writer) *ArrowWriter {
+	return &ArrowWriter{
+		BatchRows: ArrowBatchRowsDefault,
+		FileTypes: c,
+		w:         bufio.NewWriter(wtr),
+	}
+}
+
+func (a *ArrowWriter) write(b []byte) error {
+	n, e := a.w.Write(b)
+	a.offset += int64(n)
+	return e
+}
+
+func (a *ArrowWriter) message(meta []byte, body []byte) (arrowBlock, error) {
+	var block arrowBlock = arrowBlock{
+		Offset:     a.offset,
+		MetaLength: int32(8 + len(meta)),
+		BodyLength: int64(len(body)),
+	}
+	var prefix []byte = binary.LittleEndian.AppendUint32(
+		[]byte{0xff, 0xff, 0xff, 0xff},
+		uint32(len(meta)),
+	)
+	return block, errors.Join(a.write(prefix), a.write(meta), a.write(body))
+}
+
+func (a *ArrowWriter) start() error {
+	if a.started {
+		return nil
+	}
+	a.started = true
+
+	if a.File {
+		e := a.write([]byte(arrowFileMagic + "\x00\x00"))
+		if nil != e {
+			return e
+		}
+	}
+	_, e := a.message(arrowMessage(arrowHeaderSchema, arrowSchema(), 0), nil)
+	return e
+}
+
+type arrowBody struct {
+	Data    []byte
+	Buffers []byte
+}
+
+func (b *arrowBody) buffer(data []byte) {
+	b.Buffers = binary.LittleEndian.AppendUint64(b.Buffers, uint64(len(b.Data)))
+	b.Buffers = binary.LittleEndian.AppendUint64(b.Buffers, uint64(len(data)))
+	b.Data = append(b.Data, data...)
+	for 0 != len(b.Data)%8 {
+		b.Data = append(b.Data, 0)
+	}
+}
+
+func (b *arrowBody) strings(values []string) {
+	var offsets []byte = make([]byte, 0, 4*(len(values)+1))
+	var data []byte
+	offsets = binary.LittleEndian.AppendUint32(offsets, 0)
+	for _, v := range values {
+		data = append(data, v...)
+		offsets = binary.LittleEndian.AppendUint32(offsets, uint32(len(data)))
+	}
+	b.buffer(nil)
+	b.buffer(offsets)
+	b.buffer(data)
+}
+
+func (b *arrowBody) int64s(values []int64) {
+	var data []byte = make([]byte, 0, 8*len(values))
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint64(data, uint64(v))
+	}
+	b.buffer(nil)
+	b.buffer(data)
+}
+
+func (a *ArrowWriter) flushBatch() error {
+	e := a.start()
+	if nil != e || 0 == len(a.rows) {
+		return e
+	}
+
+	var n int = len(a.rows)
+	var paths []string = make([]string, 0, n)
+	var sizes []int64 = make([]int64, 0, n)
+	var mtimes []int64 = make([]int64, 0, n)
+	var types []string = make([]string, 0, n)
+	for _, s := range a.rows {
+		paths = append(paths, s.Path)
+		sizes = append(sizes, s.Size)
+		mtimes = append(mtimes, int64(s.Modified))
+		types = append(types, a.FileTypes(s.FileType))
+	}
+
+	var body arrowBody
+	body.strings(paths)
+	body.int64s(sizes)
+	body.int64s(mtimes)
+	body.strings(types)
+
+	var nodes []byte
+	for range arrowColumns {
+		nodes = binary.LittleEndian.AppendUint64(nodes, uint64(n))
+		nodes = binary.LittleEndian.AppendUint64(nodes, 0)
+	}
+
+	var batch fbTable = fbTable{
+		{ID: 0, Value: int64(n)},
+		{ID: 1, Value: fbStructs{Count: len(arrowColumns), Data: nodes}},
+		{ID: 2, Value: fbStructs{Count: len(body.Buffers) / 16, Data: body.Buffers}},
+	}
+
+	block, e := a.message(
+		arrowMessage(arrowHeaderRecordBatch, batch, int64(len(body.Data))),
+		body.Data,
+	)
+	a.blocks = append(a.blocks, block)
+	a.rows = a.rows[:0]
+	return e
+}
+
+func (a *ArrowWriter) Write(s BasicStat) error {
+	a.rows = append(a.rows, s)
+	if len(a.rows) < a.BatchRows {
+		return nil
+	}
+	return a.flushBatch()
+}
+
+func (a *ArrowWriter) footer() []byte {
+	var blocks []byte
+	for _, b := range a.blocks {
+		blocks = binary.LittleEndian.AppendUint64(blocks, uint64(b.Offset))
+		blocks = binary.LittleEndian.AppendUint32(blocks, uint32(b.MetaLength))
+		blocks = binary.LittleEndian.AppendUint32(blocks, 0)
+		blocks = binary.LittleEndian.AppendUint64(blocks, uint64(b.BodyLength))
+	}
+	return fbFinish(fbTable{
+		{ID: 0, Value: arrowMetadataV5},
+		{ID: 1, Value: arrowSchema()},
+		{ID: 2, Value: fbStructs{}},
+		{ID: 3, Value: fbStructs{Count: len(a.blocks), Data: blocks}},
+	})
+}
+
+func (a *ArrowWriter) Close() error {
+	e := a.flushBatch()
+	if nil != e {
+		return e
+	}
+
+	e = a.write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+	if nil != e {
+		return e
+	}
+
+	if a.File {
+		var footer []byte = a.footer()
+		footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+		e = a.write(append(footer, arrowFileMagic...))
+		if nil != e {
+			return e
+		}
+	}
+	return a.w.Flush()
+}
+
+func (c FileTypeToString) BasicStatsToArrowWriter(
+	wtr io.Writer,
+	batchRows int,
+	file bool,
+) func(iter.Seq2[BasicStat, error]) error {
+	return c.ArrowSink(batchRows, file).BasicStatsToWriter(context.Background(), wtr)
+}
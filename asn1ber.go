@@ -0,0 +1,231 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+const BerElementSizeMax int = 64 << 20
+
+var ErrInvalidBer error = errors.New("invalid ber stream")
+
+var (
+	berSequenceIndefinite []byte = []byte{0x30, 0x80}
+	berEndOfContents      []byte = []byte{0x00, 0x00}
+)
+
+type Asn1BerWriter struct {
+	Layout Asn1Layout
+
+	w       *bufio.Writer
+	started bool
+}
+
+func NewAsn1BerWriter(wtr io.Writer, l Asn1Layout) *Asn1BerWriter {
+	return &Asn1BerWriter{Layout: l, w: bufio.NewWriter(wtr)}
+}
+
+func (a *Asn1BerWriter) start() error {
+	if a.started {
+		return nil
+	}
+	a.started = true
+
+	_, e := a.w.Write(berSequenceIndefinite)
+	if nil != e || Asn1LayoutVersioned != a.Layout {
+		return e
+	}
+
+	version, e := asn1.Marshal(Asn1VersionCurrent)
+	if nil != e {
+		return e
+	}
+	_, e = a.w.Write(append(version, berSequenceIndefinite...))
+	return e
+}
+
+func (a *Asn1BerWriter) Write(s BasicStat) error {
+	e := a.start()
+	if nil != e {
+		return e
+	}
+
+	var der []byte
+	switch a.Layout {
+	case Asn1LayoutCompat:
+		der, e = asn1.Marshal(s)
+	case Asn1LayoutVersioned:
+		der, e = asn1.Marshal(s.ToTagged())
+	default:
+		e = fmt.Errorf("%w: %d", ErrUnknownAsn1Layout, a.Layout)
+	}
+	if nil != e {
+		return e
+	}
+
+	_, e = a.w.Write(der)
+	return e
+}
+
+func (a *Asn1BerWriter) Close() error {
+	e := a.start()
+	if nil != e {
+		return e
+	}
+
+	_, e = a.w.Write(berEndOfContents)
+	if nil == e && Asn1LayoutVersioned == a.Layout {
+		_, e = a.w.Write(berEndOfContents)
+	}
+	if nil != e {
+		return e
+	}
+	return a.w.Flush()
+}
+
+func (l Asn1Layout) BerSink() SinkFactory {
+	return func(_ context.Context, wtr io.Writer) (Sink, error) {
+		var a *Asn1BerWriter = NewAsn1BerWriter(wtr, l)
+		return StatSink{
+			Stat: a.Write,
+			Done: a.Close,
+			Fail: func(error) error { return a.w.Flush() },
+		}, nil
+	}
+}
+
+func (l Asn1Layout) BasicStatsToBerWriter(
+	wtr io.Writer,
+) func(iter.Seq2[BasicStat, error]) error {
+	return l.BerSink().BasicStatsToWriter(context.Background(), wtr)
+}
+
+func berExpect(br *bufio.Reader, want []byte) error {
+	var got []byte = make([]byte, len(want))
+	_, e := io.ReadFull(br, got)
+	if nil != e {
+		return fmt.Errorf("%w: %w", ErrInvalidBer, e)
+	}
+	if !bytes.Equal(want, got) {
+		return fmt.Errorf("%w: got % x, want % x", ErrInvalidBer, got, want)
+	}
+	return nil
+}
+
+func berElement(br *bufio.Reader) (der []byte, end bool, e error) {
+	head, e := br.Peek(2)
+	if nil != e {
+		return nil, false, fmt.Errorf("%w: missing end-of-contents: %w", ErrInvalidBer, e)
+	}
+	if bytes.Equal(berEndOfContents, head) {
+		_, e = br.Discard(2)
+		return nil, true, e
+	}
+
+	var tag, first byte = head[0], head[1]
+	switch {
+	case 0x1f == tag&0x1f:
+		return nil, false, fmt.Errorf("%w: high tag number form", ErrInvalidBer)
+	case 0x80 == first:
+		return nil, false, fmt.Errorf("%w: nested indefinite length", ErrInvalidBer)
+	}
+
+	var lengthBytes int
+	var length int = int(first)
+	if 0x80 < first {
+		lengthBytes = int(first & 0x7f)
+		if 4 < lengthBytes {
+			return nil, false, fmt.Errorf("%w: length of %d bytes", ErrInvalidBer, lengthBytes)
+		}
+		raw, e := br.Peek(2 + lengthBytes)
+		if nil != e {
+			return nil, false, fmt.Errorf("%w: %w", ErrInvalidBer, e)
+		}
+		length = 0
+		for _, b := range raw[2:] {
+			length = length<<8 | int(b)
+		}
+	}
+	if BerElementSizeMax < length {
+		return nil, false, fmt.Errorf("%w: element of %d bytes", ErrInvalidBer, length)
+	}
+
+	der = make([]byte, 2+lengthBytes+length)
+	_, e = io.ReadFull(br, der)
+	if nil != e {
+		return nil, false, fmt.Errorf("%w: %w", ErrInvalidBer, e)
+	}
+	return der, false, nil
+}
+
+func berVersion(br *bufio.Reader) error {
+	der, end, e := berElement(br)
+	switch {
+	case nil != e:
+		return e
+	case end:
+		return fmt.Errorf("%w: missing version", ErrInvalidBer)
+	}
+
+	version, e := unmarshalDer[int](der)
+	if nil != e {
+		return e
+	}
+	if version < 1 {
+		return fmt.Errorf("%w: %d", ErrUnsupportedAsn1Version, version)
+	}
+	return berExpect(br, berSequenceIndefinite)
+}
+
+func (l Asn1Layout) ReaderToBasicStatsBer(rdr io.Reader) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		var empty BasicStat
+		var br *bufio.Reader = bufio.NewReader(rdr)
+
+		e := berExpect(br, berSequenceIndefinite)
+		if nil == e && Asn1LayoutVersioned == l {
+			e = berVersion(br)
+		}
+		if nil != e {
+			yield(empty, e)
+			return
+		}
+
+		for {
+			der, end, e := berElement(br)
+			if nil != e {
+				yield(empty, e)
+				return
+			}
+			if end {
+				break
+			}
+
+			var s BasicStat
+			switch l {
+			case Asn1LayoutVersioned:
+				var t TaggedBasicStat
+				t, e = TaggedBasicStatFromAsn1Der(der)
+				s = t.ToBasicStat()
+			default:
+				s, e = BasicStatFromAsn1Der(der)
+			}
+			if !yield(s, e) || nil != e {
+				return
+			}
+		}
+
+		if Asn1LayoutVersioned == l {
+			e = berExpect(br, berEndOfContents)
+			if nil != e {
+				yield(empty, e)
+			}
+		}
+	}
+}
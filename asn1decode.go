@@ -0,0 +1,105 @@
+package names2stats
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrAsn1TrailingData       error = errors.New("trailing data after asn1 value")
+	ErrUnsupportedAsn1Version error = errors.New("unsupported asn1 version")
+)
+
+func unmarshalDer[T any](der []byte) (T, error) {
+	var ret T
+	rest, e := asn1.Unmarshal(der, &ret)
+	if nil != e {
+		return ret, e
+	}
+	if 0 < len(rest) {
+		return ret, ErrAsn1TrailingData
+	}
+	return ret, nil
+}
+
+func BasicStatFromAsn1Der(der []byte) (BasicStat, error) {
+	return unmarshalDer[BasicStat](der)
+}
+
+func TaggedBasicStatFromAsn1Der(der []byte) (TaggedBasicStat, error) {
+	return unmarshalDer[TaggedBasicStat](der)
+}
+
+func (t TaggedBasicStat) ToBasicStat() BasicStat {
+	return BasicStat{
+		Path:     t.Path,
+		Size:     t.Size,
+		Modified: t.Modified,
+		FileType: t.FileType,
+	}
+}
+
+func (v VersionedBasicStats) ToBasicStats() (BasicStats, error) {
+	if v.Version < 1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedAsn1Version, v.Version)
+	}
+
+	var ret BasicStats = make(BasicStats, 0, len(v.Stats))
+	for _, t := range v.Stats {
+		ret = append(ret, t.ToBasicStat())
+	}
+	return ret, nil
+}
+
+func BasicStatsFromAsn1Der(der []byte) (BasicStats, error) {
+	return unmarshalDer[BasicStats](der)
+}
+
+func BasicStatsFromAsn1DerVersioned(der []byte) (BasicStats, error) {
+	v, e := unmarshalDer[VersionedBasicStats](der)
+	if nil != e {
+		return nil, e
+	}
+	return v.ToBasicStats()
+}
+
+func BasicStatsFromAsn1DerLayout(der []byte, l Asn1Layout) (BasicStats, error) {
+	switch l {
+	case Asn1LayoutCompat:
+		return BasicStatsFromAsn1Der(der)
+	case Asn1LayoutVersioned:
+		return BasicStatsFromAsn1DerVersioned(der)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownAsn1Layout, l)
+	}
+}
+
+func DetectAsn1Layout(der []byte) (Asn1Layout, error) {
+	var outer asn1.RawValue
+	_, e := asn1.Unmarshal(der, &outer)
+	if nil != e {
+		return Asn1LayoutCompat, e
+	}
+
+	var first asn1.RawValue
+	_, e = asn1.Unmarshal(outer.Bytes, &first)
+	switch {
+	case 0 == len(outer.Bytes):
+		return Asn1LayoutCompat, nil
+	case nil != e:
+		return Asn1LayoutCompat, e
+	case asn1.TagInteger == first.Tag:
+		return Asn1LayoutVersioned, nil
+	default:
+		return Asn1LayoutCompat, nil
+	}
+}
+
+func BasicStatsFromAsn1DerAuto(der []byte) (BasicStats, error) {
+	l, e := DetectAsn1Layout(der)
+	if nil != e {
+		return nil, e
+	}
+	return BasicStatsFromAsn1DerLayout(der, l)
+}
@@ -0,0 +1,232 @@
+package names2stats
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"errors"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+)
+
+var asn1FileTypes []FileType = []FileType{
+	FileTypeUnspecified,
+	FileTypeBlck,
+	FileTypeChar,
+	FileTypeFldr,
+	FileTypeRglr,
+	FileTypeSyml,
+	FileTypePipe,
+	FileTypeSock,
+}
+
+func randomBasicStats(rnd *rand.Rand, size int) BasicStats {
+	var ret BasicStats = make(BasicStats, rnd.Intn(size+1))
+	for i := range ret {
+		path, _ := quick.Value(reflect.TypeFor[string](), rnd)
+		ret[i] = BasicStat{
+			Path:     path.String(),
+			Size:     rnd.Int63() - rnd.Int63(),
+			Modified: UnixtimeUs(rnd.Int63() - rnd.Int63()),
+			FileType: asn1FileTypes[rnd.Intn(len(asn1FileTypes))],
+		}
+	}
+	return ret
+}
+
+var asn1QuickConfig *quick.Config = &quick.Config{
+	Values: func(args []reflect.Value, rnd *rand.Rand) {
+		args[0] = reflect.ValueOf(randomBasicStats(rnd, 32))
+		for i := 1; i < len(args); i++ {
+			args[i], _ = quick.Value(reflect.TypeFor[string](), rnd)
+		}
+	},
+}
+
+func berRoundTrip(l Asn1Layout, stats BasicStats) (BasicStats, error) {
+	var buf bytes.Buffer
+	e := l.BasicStatsToBerWriter(&buf)(func(yield func(BasicStat, error) bool) {
+		for _, s := range stats {
+			if !yield(s, nil) {
+				return
+			}
+		}
+	})
+	if nil != e {
+		return nil, e
+	}
+
+	var ret BasicStats
+	for s, e := range l.ReaderToBasicStatsBer(&buf) {
+		if nil != e {
+			return nil, e
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+func derRoundTrip(l Asn1Layout, stats BasicStats) (BasicStats, error) {
+	der, e := stats.ToAsn1DerBytesLayout(l)
+	if nil != e {
+		return nil, e
+	}
+	return BasicStatsFromAsn1DerLayout(der, l)
+}
+
+type futureTaggedBasicStat struct {
+	Path     string     `asn1:"utf8,explicit,tag:0"`
+	Size     int64      `asn1:"explicit,tag:1"`
+	Modified UnixtimeUs `asn1:"explicit,tag:2"`
+	FileType `asn1:"explicit,tag:3"`
+	Owner    string `asn1:"utf8,explicit,tag:4"`
+}
+
+type futureVersionedBasicStats struct {
+	Version int
+	Stats   []futureTaggedBasicStat
+}
+
+func toFuture(stats BasicStats, owner string) []futureTaggedBasicStat {
+	var ret []futureTaggedBasicStat = make([]futureTaggedBasicStat, 0, len(stats))
+	for _, s := range stats {
+		ret = append(ret, futureTaggedBasicStat{
+			Path:     s.Path,
+			Size:     s.Size,
+			Modified: s.Modified,
+			FileType: s.FileType,
+			Owner:    owner,
+		})
+	}
+	return ret
+}
+
+func futureDer(stats BasicStats, owner string) ([]byte, error) {
+	return asn1.Marshal(futureVersionedBasicStats{
+		Version: Asn1VersionCurrent + 1,
+		Stats:   toFuture(stats, owner),
+	})
+}
+
+func futureBer(stats BasicStats, owner string) ([]byte, error) {
+	version, e := asn1.Marshal(Asn1VersionCurrent + 1)
+	if nil != e {
+		return nil, e
+	}
+
+	var buf []byte = slices.Concat(berSequenceIndefinite, version, berSequenceIndefinite)
+	for _, f := range toFuture(stats, owner) {
+		der, e := asn1.Marshal(f)
+		if nil != e {
+			return nil, e
+		}
+		buf = append(buf, der...)
+	}
+	return slices.Concat(buf, berEndOfContents, berEndOfContents), nil
+}
+
+func TestAsn1RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var layouts map[string]Asn1Layout = map[string]Asn1Layout{
+		"compat":    Asn1LayoutCompat,
+		"versioned": Asn1LayoutVersioned,
+	}
+	var codecs = map[string]func(Asn1Layout, BasicStats) (BasicStats, error){
+		"der": derRoundTrip,
+		"ber": berRoundTrip,
+	}
+
+	for name, codec := range codecs {
+		for lname, l := range layouts {
+			t.Run(name+"-"+lname, func(t *testing.T) {
+				t.Parallel()
+
+				e := quick.Check(func(stats BasicStats) bool {
+					got, e := codec(l, stats)
+					if nil != e {
+						t.Log(e)
+						return false
+					}
+					return slices.Equal(stats, got)
+				}, asn1QuickConfig)
+				if nil != e {
+					t.Fatal(e)
+				}
+			})
+		}
+	}
+
+	t.Run("future-der", func(t *testing.T) {
+		t.Parallel()
+
+		e := quick.Check(func(stats BasicStats, owner string) bool {
+			der, e := futureDer(stats, owner)
+			if nil != e {
+				t.Log(e)
+				return false
+			}
+			got, e := BasicStatsFromAsn1DerAuto(der)
+			if nil != e {
+				t.Log(e)
+				return false
+			}
+			return slices.Equal(stats, got)
+		}, asn1QuickConfig)
+		if nil != e {
+			t.Fatal(e)
+		}
+	})
+
+	t.Run("future-ber", func(t *testing.T) {
+		t.Parallel()
+
+		e := quick.Check(func(stats BasicStats, owner string) bool {
+			ber, e := futureBer(stats, owner)
+			if nil != e {
+				t.Log(e)
+				return false
+			}
+			var got BasicStats
+			for s, e := range Asn1LayoutVersioned.ReaderToBasicStatsBer(bytes.NewReader(ber)) {
+				if nil != e {
+					t.Log(e)
+					return false
+				}
+				got = append(got, s)
+			}
+			return slices.Equal(stats, got)
+		}, asn1QuickConfig)
+		if nil != e {
+			t.Fatal(e)
+		}
+	})
+
+	t.Run("ber-oversized", func(t *testing.T) {
+		t.Parallel()
+
+		var ber []byte = slices.Concat(berSequenceIndefinite, []byte{0x04, 0x84, 0xff, 0xff, 0xff, 0xff})
+		var got error
+		for _, e := range Asn1LayoutCompat.ReaderToBasicStatsBer(bytes.NewReader(ber)) {
+			got = e
+		}
+		if !errors.Is(got, ErrInvalidBer) {
+			t.Fatalf("want ErrInvalidBer, got %v", got)
+		}
+	})
+
+	t.Run("version-zero", func(t *testing.T) {
+		t.Parallel()
+
+		der, e := asn1.Marshal(VersionedBasicStats{Version: 0})
+		if nil != e {
+			t.Fatal(e)
+		}
+		_, e = BasicStatsFromAsn1DerVersioned(der)
+		if nil == e {
+			t.Fatal("version 0 must be rejected")
+		}
+	})
+}
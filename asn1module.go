@@ -0,0 +1,291 @@
+package names2stats
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrAsn1Unsupported error = errors.New("unsupported type for asn1 module")
+
+type Asn1NamedValue struct {
+	Name  string
+	Value int
+}
+
+var FileTypeAsn1Values []Asn1NamedValue = []Asn1NamedValue{
+	{Name: "unspecified", Value: int(FileTypeUnspecified)},
+	{Name: "regular", Value: int(FileTypeRglr)},
+	{Name: "symlink", Value: int(FileTypeSyml)},
+	{Name: "character", Value: int(FileTypeChar)},
+	{Name: "block", Value: int(FileTypeBlck)},
+	{Name: "directory", Value: int(FileTypeFldr)},
+	{Name: "fifo", Value: int(FileTypePipe)},
+	{Name: "socket", Value: int(FileTypeSock)},
+}
+
+type Asn1Enum struct {
+	Name   string
+	Values []Asn1NamedValue
+}
+
+type Asn1ModuleBuilder struct {
+	Enums    map[reflect.Type]Asn1Enum
+	Comments map[reflect.Type]string
+
+	assignments []string
+	defined     map[reflect.Type]string
+}
+
+var (
+	typeEnumerated reflect.Type = reflect.TypeFor[asn1.Enumerated]()
+	typeTime       reflect.Type = reflect.TypeFor[time.Time]()
+	typeBytes      reflect.Type = reflect.TypeFor[[]byte]()
+)
+
+func asn1Identifier(goName string) string {
+	var r []rune = []rune(goName)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func asn1TypeName(t reflect.Type) string {
+	var r []rune = []rune(t.Name())
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+type asn1Params struct {
+	explicit    bool
+	optional    bool
+	application bool
+	tag         string
+	defaultVal  string
+	str         string
+	timeType    string
+	set         bool
+}
+
+func parseAsn1Params(tag string) asn1Params {
+	var p asn1Params
+	for _, part := range strings.Split(tag, ",") {
+		switch {
+		case "explicit" == part:
+			p.explicit = true
+		case "optional" == part:
+			p.optional = true
+		case "application" == part:
+			p.application = true
+		case "set" == part:
+			p.set = true
+		case strings.HasPrefix(part, "tag:"):
+			p.tag = strings.TrimPrefix(part, "tag:")
+		case strings.HasPrefix(part, "default:"):
+			p.defaultVal = strings.TrimPrefix(part, "default:")
+		case "utf8" == part, "ia5" == part, "printable" == part, "numeric" == part:
+			p.str = part
+		case "utc" == part, "generalized" == part:
+			p.timeType = part
+		}
+	}
+	return p
+}
+
+func (p asn1Params) stringType() string {
+	switch p.str {
+	case "utf8":
+		return "UTF8String"
+	case "ia5":
+		return "IA5String"
+	case "numeric":
+		return "NumericString"
+	default:
+		return "PrintableString"
+	}
+}
+
+func (p asn1Params) wrap(typ string) string {
+	if "" == p.tag {
+		return typ
+	}
+
+	var class string = ""
+	if p.application {
+		class = "APPLICATION "
+	}
+
+	var mode string = "IMPLICIT"
+	if p.explicit {
+		mode = "EXPLICIT"
+	}
+
+	return fmt.Sprintf("[%s%s] %s %s", class, p.tag, mode, typ)
+}
+
+func (b *Asn1ModuleBuilder) typeOf(t reflect.Type, p asn1Params) (string, error) {
+	switch {
+	case typeTime == t:
+		if "generalized" == p.timeType {
+			return "GeneralizedTime", nil
+		}
+		return "UTCTime", nil
+	case typeBytes == t:
+		return "OCTET STRING", nil
+	}
+
+	_, isEnum := b.Enums[t]
+	if isEnum || typeEnumerated == t {
+		return b.defineEnum(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "BOOLEAN", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "INTEGER", nil
+	case reflect.String:
+		return p.stringType(), nil
+	case reflect.Slice:
+		elem, e := b.typeOf(t.Elem(), asn1Params{})
+		if nil != e {
+			return "", e
+		}
+		if p.set {
+			return "SET OF " + elem, nil
+		}
+		return "SEQUENCE OF " + elem, nil
+	case reflect.Struct:
+		return b.defineStruct(t)
+	default:
+		return "", fmt.Errorf("%w: %v", ErrAsn1Unsupported, t)
+	}
+}
+
+func (b *Asn1ModuleBuilder) defineEnum(t reflect.Type) (string, error) {
+	name, found := b.defined[t]
+	if found {
+		return name, nil
+	}
+
+	enum, found := b.Enums[t]
+	if !found {
+		return "", fmt.Errorf("%w: enum values of %v missing", ErrAsn1Unsupported, t)
+	}
+
+	name = enum.Name
+	b.defined[t] = name
+
+	var items []string = make([]string, 0, len(enum.Values))
+	for _, v := range enum.Values {
+		items = append(items, fmt.Sprintf("    %s(%d)", v.Name, v.Value))
+	}
+
+	b.assignments = append(b.assignments, fmt.Sprintf(
+		"%s ::= ENUMERATED {\n%s\n}",
+		name,
+		strings.Join(items, ",\n"),
+	))
+	return name, nil
+}
+
+func (b *Asn1ModuleBuilder) defineStruct(t reflect.Type) (string, error) {
+	name, found := b.defined[t]
+	if found {
+		return name, nil
+	}
+
+	name = asn1TypeName(t)
+	b.defined[t] = name
+
+	var codes []string
+	var comments []string
+	for i := range t.NumField() {
+		var f reflect.StructField = t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		var p asn1Params = parseAsn1Params(f.Tag.Get("asn1"))
+		typ, e := b.typeOf(f.Type, p)
+		if nil != e {
+			return "", fmt.Errorf("%s.%s: %w", t.Name(), f.Name, e)
+		}
+
+		var code string = fmt.Sprintf("    %s %s", asn1Identifier(f.Name), p.wrap(typ))
+		switch {
+		case "" != p.defaultVal:
+			code += " DEFAULT " + p.defaultVal
+		case p.optional:
+			code += " OPTIONAL"
+		}
+
+		codes = append(codes, code)
+		comments = append(comments, b.Comments[f.Type])
+	}
+
+	var lines []string = make([]string, 0, len(codes))
+	for i, code := range codes {
+		if i < len(codes)-1 {
+			code += ","
+		}
+		if "" != comments[i] {
+			code += " -- " + comments[i]
+		}
+		lines = append(lines, code)
+	}
+	var body string = strings.Join(lines, "\n")
+
+	b.assignments = append(b.assignments, fmt.Sprintf(
+		"%s ::= SEQUENCE {\n%s\n}",
+		name,
+		body,
+	))
+	return name, nil
+}
+
+func (b *Asn1ModuleBuilder) Define(name string, t reflect.Type) error {
+	typ, e := b.typeOf(t, asn1Params{})
+	if nil != e {
+		return e
+	}
+	if typ != name {
+		b.assignments = append(b.assignments, fmt.Sprintf("%s ::= %s", name, typ))
+	}
+	return nil
+}
+
+func (b *Asn1ModuleBuilder) Build(moduleName string) string {
+	return fmt.Sprintf(
+		"%s DEFINITIONS ::= BEGIN\n\n%s\n\nEND\n",
+		moduleName,
+		strings.Join(b.assignments, "\n\n"),
+	)
+}
+
+func NewAsn1ModuleBuilder() *Asn1ModuleBuilder {
+	return &Asn1ModuleBuilder{
+		Enums: map[reflect.Type]Asn1Enum{
+			typeEnumerated: {Name: "FileType", Values: FileTypeAsn1Values},
+		},
+		Comments: map[reflect.Type]string{
+			reflect.TypeFor[UnixtimeUs](): "unixtime in microseconds",
+		},
+		defined: map[reflect.Type]string{},
+	}
+}
+
+func BasicStatsAsn1Module(moduleName string) (string, error) {
+	var b *Asn1ModuleBuilder = NewAsn1ModuleBuilder()
+	e := errors.Join(
+		b.Define("BasicStats", reflect.TypeFor[BasicStats]()),
+		b.Define("VersionedBasicStats", reflect.TypeFor[VersionedBasicStats]()),
+	)
+	if nil != e {
+		return "", e
+	}
+	return b.Build(moduleName), nil
+}
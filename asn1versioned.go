@@ -0,0 +1,76 @@
+package names2stats
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+var ErrUnknownAsn1Layout error = errors.New("unknown asn1 layout")
+
+const Asn1VersionCurrent int = 1
+
+type Asn1Layout int
+
+const (
+	Asn1LayoutCompat Asn1Layout = iota
+	Asn1LayoutVersioned
+)
+
+func ParseAsn1Layout(s string) (Asn1Layout, error) {
+	switch s {
+	case "compat":
+		return Asn1LayoutCompat, nil
+	case "versioned":
+		return Asn1LayoutVersioned, nil
+	default:
+		return Asn1LayoutCompat, fmt.Errorf("%w: %s", ErrUnknownAsn1Layout, s)
+	}
+}
+
+type TaggedBasicStat struct {
+	Path     string     `asn1:"utf8,explicit,tag:0"`
+	Size     int64      `asn1:"explicit,tag:1"`
+	Modified UnixtimeUs `asn1:"explicit,tag:2"`
+	FileType `asn1:"explicit,tag:3"`
+}
+
+func (b BasicStat) ToTagged() TaggedBasicStat {
+	return TaggedBasicStat{
+		Path:     b.Path,
+		Size:     b.Size,
+		Modified: b.Modified,
+		FileType: b.FileType,
+	}
+}
+
+type VersionedBasicStats struct {
+	Version int
+	Stats   []TaggedBasicStat
+}
+
+func (b BasicStats) ToVersioned() VersionedBasicStats {
+	var stats []TaggedBasicStat = make([]TaggedBasicStat, 0, len(b))
+	for _, s := range b {
+		stats = append(stats, s.ToTagged())
+	}
+	return VersionedBasicStats{
+		Version: Asn1VersionCurrent,
+		Stats:   stats,
+	}
+}
+
+func (b BasicStats) ToAsn1DerBytesVersioned() ([]byte, error) {
+	return asn1.Marshal(b.ToVersioned())
+}
+
+func (b BasicStats) ToAsn1DerBytesLayout(l Asn1Layout) ([]byte, error) {
+	switch l {
+	case Asn1LayoutCompat:
+		return b.ToAsn1DerBytes()
+	case Asn1LayoutVersioned:
+		return b.ToAsn1DerBytesVersioned()
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownAsn1Layout, l)
+	}
+}
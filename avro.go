@@ -0,0 +1,171 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"iter"
+	"slices"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+const AvroBlockRowsDefault int = 4096
+
+const (
+	AvroCodecNull    string = "null"
+	AvroCodecDeflate string = "deflate"
+	AvroCodecSnappy  string = "snappy"
+)
+
+var AvroCodecs []string = []string{AvroCodecNull, AvroCodecDeflate, AvroCodecSnappy}
+
+var ErrUnknownAvroCodec error = errors.New("unknown avro codec")
+
+const AvroBasicStatSchema string = `{"type":"record","name":"BasicStat",` +
+	`"namespace":"names2stats","fields":[` +
+	`{"name":"path","type":"string"},` +
+	`{"name":"size","type":"long"},` +
+	`{"name":"modified_time","type":{"type":"long","logicalType":"timestamp-micros"}},` +
+	`{"name":"file_type","type":"string"}]}`
+
+func appendAvroLong(buf []byte, v int64) []byte {
+	return binary.AppendVarint(buf, v)
+}
+
+func appendAvroString(buf []byte, s string) []byte {
+	return append(appendAvroLong(buf, int64(len(s))), s...)
+}
+
+func ParseAvroCodec(s string) (string, error) {
+	if !slices.Contains(AvroCodecs, s) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownAvroCodec, s)
+	}
+	return s, nil
+}
+
+type AvroWriter struct {
+	BlockRows int
+	Codec     string
+	FileTypes FileTypeToString
+
+	w       *bufio.Writer
+	sync    [16]byte
+	started bool
+	block   []byte
+	rows    int64
+}
+
+func (c FileTypeToString) NewAvroWriter(wtr io.Writer, codec string) *AvroWriter {
+	var a *AvroWriter = &AvroWriter{
+		BlockRows: AvroBlockRowsDefault,
+		Codec:     codec,
+		FileTypes: c,
+		w:         bufio.NewWriter(wtr),
+	}
+	_, _ = rand.Read(a.sync[:])
+	return a
+}
+
+func (a *AvroWriter) start() error {
+	if a.started {
+		return nil
+	}
+	a.started = true
+
+	var header []byte = []byte("Obj\x01")
+	header = appendAvroLong(header, 2)
+	header = appendAvroString(header, "avro.schema")
+	header = appendAvroString(header, AvroBasicStatSchema)
+	header = appendAvroString(header, "avro.codec")
+	header = appendAvroString(header, a.Codec)
+	header = appendAvroLong(header, 0)
+	header = append(header, a.sync[:]...)
+
+	_, e := a.w.Write(header)
+	return e
+}
+
+func (a *AvroWriter) compress(data []byte) ([]byte, error) {
+	switch a.Codec {
+	case AvroCodecDeflate:
+		var buf bytes.Buffer
+		fw, e := flate.NewWriter(&buf, flate.DefaultCompression)
+		if nil != e {
+			return nil, e
+		}
+		_, e = fw.Write(data)
+		e = errors.Join(e, fw.Close())
+		return buf.Bytes(), e
+	case AvroCodecSnappy:
+		return binary.BigEndian.AppendUint32(
+			snappy.Encode(nil, data),
+			crc32.ChecksumIEEE(data),
+		), nil
+	case AvroCodecNull, "":
+		return data, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAvroCodec, a.Codec)
+	}
+}
+
+func (a *AvroWriter) flushBlock() error {
+	e := a.start()
+	if nil != e || 0 == a.rows {
+		return e
+	}
+
+	data, e := a.compress(a.block)
+	if nil != e {
+		return e
+	}
+
+	var head []byte = appendAvroLong(nil, a.rows)
+	head = appendAvroLong(head, int64(len(data)))
+	_, e = a.w.Write(head)
+	if nil == e {
+		_, e = a.w.Write(data)
+	}
+	if nil == e {
+		_, e = a.w.Write(a.sync[:])
+	}
+
+	a.block = a.block[:0]
+	a.rows = 0
+	return e
+}
+
+func (a *AvroWriter) Write(s BasicStat) error {
+	a.block = appendAvroString(a.block, s.Path)
+	a.block = appendAvroLong(a.block, s.Size)
+	a.block = appendAvroLong(a.block, int64(s.Modified))
+	a.block = appendAvroString(a.block, a.FileTypes(s.FileType))
+	a.rows++
+
+	if a.rows < int64(a.BlockRows) {
+		return nil
+	}
+	return a.flushBlock()
+}
+
+func (a *AvroWriter) Close() error {
+	e := a.flushBlock()
+	if nil != e {
+		return e
+	}
+	return a.w.Flush()
+}
+
+func (c FileTypeToString) BasicStatsToAvroWriter(
+	wtr io.Writer,
+	codec string,
+) func(iter.Seq2[BasicStat, error]) error {
+	return c.AvroSink(codec).BasicStatsToWriter(context.Background(), wtr)
+}
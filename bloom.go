@@ -0,0 +1,72 @@
+package names2stats
+
+import (
+	"errors"
+	"hash/maphash"
+	"math"
+)
+
+var ErrInvalidBloomParams error = errors.New("invalid bloom filter parameters")
+
+type BloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes uint64
+	seed1  maphash.Seed
+	seed2  maphash.Seed
+}
+
+func NewBloomFilter(capacity uint64, fpRate float64) (*BloomFilter, error) {
+	if 0 == capacity || fpRate <= 0 || 1 <= fpRate {
+		return nil, ErrInvalidBloomParams
+	}
+
+	var n float64 = float64(capacity)
+	var m float64 = math.Ceil(-n * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	var k float64 = math.Max(1, math.Round(m/n*math.Ln2))
+
+	var size uint64 = uint64(m)
+	return &BloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: uint64(k),
+		seed1:  maphash.MakeSeed(),
+		seed2:  maphash.MakeSeed(),
+	}, nil
+}
+
+func (b *BloomFilter) positions(key string, f func(pos uint64) bool) bool {
+	var h1 uint64 = maphash.String(b.seed1, key)
+	var h2 uint64 = maphash.String(b.seed2, key) | 1
+	for i := range b.hashes {
+		if !f((h1 + i*h2) % b.size) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *BloomFilter) MayContain(key string) bool {
+	return b.positions(key, func(pos uint64) bool {
+		return 0 != b.bits[pos/64]&(1<<(pos%64))
+	})
+}
+
+func (b *BloomFilter) Add(key string) {
+	b.positions(key, func(pos uint64) bool {
+		b.bits[pos/64] |= 1 << (pos % 64)
+		return true
+	})
+}
+
+func (b *BloomFilter) FirstSeen(key string) bool {
+	var absent bool = !b.MayContain(key)
+	if absent {
+		b.Add(key)
+	}
+	return absent
+}
+
+func (b *BloomFilter) FirstSeenCase(c PathCase) func(string) bool {
+	return func(name string) bool { return b.FirstSeen(c.Fold(name)) }
+}
@@ -0,0 +1,106 @@
+package names2stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"iter"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+const jsonHex string = "0123456789abcdef"
+
+func appendJSONString[T string | []byte](buf []byte, s T) []byte {
+	buf = append(buf, '"')
+	var start int
+	for i := 0; i < len(s); {
+		var c byte = s[i]
+		if c < utf8.RuneSelf {
+			var esc byte
+			switch c {
+			case '"', '\\':
+				esc = c
+			case '\b':
+				esc = 'b'
+			case '\f':
+				esc = 'f'
+			case '\n':
+				esc = 'n'
+			case '\r':
+				esc = 'r'
+			case '\t':
+				esc = 't'
+			}
+			switch {
+			case 0 != esc:
+				buf = append(append(buf, s[start:i]...), '\\', esc)
+			case c < 0x20, '<' == c, '>' == c, '&' == c:
+				buf = append(append(buf, s[start:i]...), '\\', 'u', '0', '0', jsonHex[c>>4], jsonHex[c&0xf])
+			default:
+				i++
+				continue
+			}
+			i++
+			start = i
+			continue
+		}
+
+		var head [utf8.UTFMax]byte
+		r, size := utf8.DecodeRune(head[:copy(head[:], s[i:])])
+		switch {
+		case utf8.RuneError == r && 1 == size:
+			buf = append(append(buf, s[start:i]...), "\ufffd"...)
+		case '\u2028' == r, '\u2029' == r:
+			buf = append(append(buf, s[start:i]...), '\\', 'u', '2', '0', '2', jsonHex[r&0xf])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	return append(append(buf, s[start:]...), '"')
+}
+
+func AppendJSONString(buf []byte, s string) []byte { return appendJSONString(buf, s) }
+
+func AppendJSONBytes(buf []byte, b []byte) []byte { return appendJSONString(buf, b) }
+
+func (c FileTypeToString) AppendBasicStatJSON(buf []byte, s BasicStat) ([]byte, error) {
+	var t time.Time = s.Modified.ToTime()
+	if y := t.Year(); y < 0 || 9999 < y {
+		raw, e := json.Marshal(s.ToJsonObj(c))
+		return append(buf, raw...), e
+	}
+
+	buf = append(buf, `{"path":`...)
+	buf = appendJSONString(buf, s.Path)
+	buf = append(buf, `,"size":`...)
+	buf = strconv.AppendInt(buf, s.Size, 10)
+	buf = append(buf, `,"modified_time":"`...)
+	buf = t.AppendFormat(buf, time.RFC3339Nano)
+	buf = append(buf, `","file_type":`...)
+	buf = appendJSONString(buf, c(s.FileType))
+	return append(buf, '}'), nil
+}
+
+func (d NameDelim) ReaderToNameBytes(rdr io.Reader) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		var s *bufio.Scanner = bufio.NewScanner(rdr)
+		if NameDelimNewline != d {
+			s.Split(d.split)
+		}
+		for s.Scan() {
+			if !yield(s.Bytes(), nil) {
+				return
+			}
+		}
+
+		e := s.Err()
+		if nil != e {
+			yield(nil, e)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package names2stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"testing"
+)
+
+func benchStat() BasicStat {
+	return BasicStat{
+		Path:     "var/log/app/2026/10/14/access.log.gz",
+		Size:     123456789,
+		Modified: UnixtimeUs(1760400000123456),
+		FileType: FileTypeRglr,
+	}
+}
+
+func BenchmarkAppendBasicStatJSON(b *testing.B) {
+	var s BasicStat = benchStat()
+	var t2s FileTypeToString = FileTypeToStringDefault
+
+	b.Run("append", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for b.Loop() {
+			var e error
+			buf, e = t2s.AppendBasicStatJSON(buf[:0], s)
+			if nil != e {
+				b.Fatal(e)
+			}
+		}
+	})
+
+	b.Run("marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, e := json.Marshal(s.ToJsonObj(t2s))
+			if nil != e {
+				b.Fatal(e)
+			}
+		}
+	})
+}
+
+func benchNames(n int) []byte {
+	var buf []byte
+	for i := range n {
+		buf = append(buf, "var/log/app/access.log."...)
+		buf = strconv.AppendInt(buf, int64(i), 10)
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+var (
+	benchNameBytes  []byte
+	benchNameString string
+)
+
+func BenchmarkReaderToNameBytes(b *testing.B) {
+	b.Run("bytes", func(b *testing.B) {
+		var input []byte = benchNames(b.N)
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for name, e := range NameDelimNewline.ReaderToNameBytes(bytes.NewReader(input)) {
+			if nil != e {
+				b.Fatal(e)
+			}
+			benchNameBytes = name
+		}
+	})
+
+	b.Run("string", func(b *testing.B) {
+		var input []byte = benchNames(b.N)
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for name, e := range NameDelimNewline.ReaderToNameIter(bytes.NewReader(input)) {
+			if nil != e {
+				b.Fatal(e)
+			}
+			benchNameString = name
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package names2stats
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type CacheMetrics struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+	Entries   int    `json:"entries"`
+}
+
+type cacheEntry struct {
+	path    string
+	stat    BasicStat
+	expires time.Time
+}
+
+type StatCache struct {
+	TTL        time.Duration
+	MaxEntries int
+	Now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+func NewStatCache(ttl time.Duration, maxEntries int) *StatCache {
+	return &StatCache{
+		TTL:        ttl,
+		MaxEntries: maxEntries,
+		Now:        time.Now,
+	}
+}
+
+func (c *StatCache) init() {
+	if nil == c.entries {
+		c.entries = map[string]*list.Element{}
+		c.order = list.New()
+	}
+}
+
+func (c *StatCache) now() time.Time {
+	if nil == c.Now {
+		return time.Now()
+	}
+	return c.Now()
+}
+
+func (c *StatCache) expired(ent *cacheEntry) bool {
+	return 0 < c.TTL && !c.now().Before(ent.expires)
+}
+
+func (c *StatCache) removeElement(el *list.Element) {
+	var ent *cacheEntry = el.Value.(*cacheEntry)
+	delete(c.entries, ent.path)
+	c.order.Remove(el)
+}
+
+func (c *StatCache) Get(path string) (BasicStat, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	var empty BasicStat
+
+	el, found := c.entries[path]
+	if !found {
+		c.misses.Add(1)
+		return empty, false
+	}
+
+	var ent *cacheEntry = el.Value.(*cacheEntry)
+	if c.expired(ent) {
+		c.removeElement(el)
+		c.evictions.Add(1)
+		c.misses.Add(1)
+		return empty, false
+	}
+
+	c.order.MoveToBack(el)
+	c.hits.Add(1)
+	return ent.stat, true
+}
+
+func (c *StatCache) evictLeastRecent() {
+	c.removeElement(c.order.Front())
+	c.evictions.Add(1)
+}
+
+func (c *StatCache) Put(path string, stat BasicStat) {
+	if c.MaxEntries < 1 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	var expires time.Time = c.now().Add(c.TTL)
+
+	el, found := c.entries[path]
+	if found {
+		var ent *cacheEntry = el.Value.(*cacheEntry)
+		ent.stat, ent.expires = stat, expires
+		c.order.MoveToBack(el)
+		return
+	}
+
+	for c.MaxEntries <= c.order.Len() {
+		c.evictLeastRecent()
+	}
+
+	c.entries[path] = c.order.PushBack(&cacheEntry{
+		path:    path,
+		stat:    stat,
+		expires: expires,
+	})
+}
+
+func (c *StatCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init()
+
+	clear(c.entries)
+	c.order.Init()
+}
+
+func (c *StatCache) Metrics() CacheMetrics {
+	c.mu.Lock()
+	c.init()
+	var entries int = c.order.Len()
+	c.mu.Unlock()
+
+	return CacheMetrics{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+func (c *StatCache) Wrap(original FilenameToBasicStat) FilenameToBasicStat {
+	return func(fullpath string) (BasicStat, error) {
+		cached, found := c.Get(fullpath)
+		if found {
+			return cached, nil
+		}
+
+		s, e := original(fullpath)
+		if nil == e {
+			c.Put(fullpath, s)
+		}
+		return s, e
+	}
+}
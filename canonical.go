@@ -0,0 +1,144 @@
+package names2stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+func canonicalPrepare(v any) any {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC()
+	case Record:
+		var ret Record = make(Record, 0, len(t))
+		for _, f := range t {
+			ret = append(ret, Field{Key: f.Key, Value: canonicalPrepare(f.Value)})
+		}
+		return unescapedRecord(ret)
+	default:
+		return v
+	}
+}
+
+func canonicalCompare(a, b string) int {
+	return slices.Compare(utf16.Encode([]rune(a)), utf16.Encode([]rune(b)))
+}
+
+func appendCanonicalString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case '"' == r, '\\' == r:
+			buf = append(buf, '\\', byte(r))
+		case '\b' == r:
+			buf = append(buf, '\\', 'b')
+		case '\f' == r:
+			buf = append(buf, '\\', 'f')
+		case '\n' == r:
+			buf = append(buf, '\\', 'n')
+		case '\r' == r:
+			buf = append(buf, '\\', 'r')
+		case '\t' == r:
+			buf = append(buf, '\\', 't')
+		case r < 0x20:
+			buf = fmt.Appendf(buf, "\\u%04x", r)
+		default:
+			buf = utf8.AppendRune(buf, r)
+		}
+	}
+	return append(buf, '"')
+}
+
+func appendCanonicalNumber(buf []byte, n json.Number) ([]byte, error) {
+	var s string = n.String()
+	if !strings.ContainsAny(s, ".eE") {
+		i, e := strconv.ParseInt(s, 10, 64)
+		if nil == e {
+			return strconv.AppendInt(buf, i, 10), nil
+		}
+	}
+
+	f, e := n.Float64()
+	if nil != e {
+		return buf, e
+	}
+	raw, e := json.Marshal(f)
+	return append(buf, raw...), e
+}
+
+func appendCanonical(buf []byte, v any) ([]byte, error) {
+	switch t := v.(type) {
+	case nil:
+		return append(buf, "null"...), nil
+	case bool:
+		return strconv.AppendBool(buf, t), nil
+	case string:
+		return appendCanonicalString(buf, t), nil
+	case json.Number:
+		return appendCanonicalNumber(buf, t)
+	case []any:
+		buf = append(buf, '[')
+		for i, item := range t {
+			if 0 < i {
+				buf = append(buf, ',')
+			}
+			var e error
+			buf, e = appendCanonical(buf, item)
+			if nil != e {
+				return buf, e
+			}
+		}
+		return append(buf, ']'), nil
+	case map[string]any:
+		var keys []string = make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		slices.SortFunc(keys, canonicalCompare)
+
+		buf = append(buf, '{')
+		for i, key := range keys {
+			if 0 < i {
+				buf = append(buf, ',')
+			}
+			buf = append(appendCanonicalString(buf, key), ':')
+
+			var e error
+			buf, e = appendCanonical(buf, t[key])
+			if nil != e {
+				return buf, e
+			}
+		}
+		return append(buf, '}'), nil
+	default:
+		return buf, fmt.Errorf("unexpected json value: %T", v)
+	}
+}
+
+func AppendCanonicalJSON(buf []byte, v any) ([]byte, error) {
+	raw, e := json.Marshal(canonicalPrepare(v))
+	if nil != e {
+		return buf, e
+	}
+
+	var dec *json.Decoder = json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var parsed any
+	e = dec.Decode(&parsed)
+	if nil != e {
+		return buf, e
+	}
+	return appendCanonical(buf, parsed)
+}
+
+func (r Record) CanonicalJSON() ([]byte, error) { return AppendCanonicalJSON(nil, r) }
@@ -0,0 +1,184 @@
+package names2stats
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+var ErrCborUnsupported error = errors.New("unsupported type for cbor")
+
+const (
+	cborUint   byte = 0 << 5
+	cborNegInt byte = 1 << 5
+	cborBytes  byte = 2 << 5
+	cborText   byte = 3 << 5
+	cborArray  byte = 4 << 5
+	cborMap    byte = 5 << 5
+	cborTag    byte = 6 << 5
+	cborSimple byte = 7 << 5
+)
+
+const cborTagEpoch uint64 = 1
+
+func appendCborHead(buf []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+func appendCborInt(buf []byte, i int64) []byte {
+	if i < 0 {
+		return appendCborHead(buf, cborNegInt, uint64(-(i + 1)))
+	}
+	return appendCborHead(buf, cborUint, uint64(i))
+}
+
+func appendCborFloat(buf []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(buf, cborSimple|27), math.Float64bits(f))
+}
+
+func appendCborText(buf []byte, s string) []byte {
+	return append(appendCborHead(buf, cborText, uint64(len(s))), s...)
+}
+
+func appendCborTime(buf []byte, t time.Time) []byte {
+	buf = appendCborHead(buf, cborTag, cborTagEpoch)
+	if 0 == t.Nanosecond() {
+		return appendCborInt(buf, t.Unix())
+	}
+	return appendCborFloat(buf, float64(t.UnixMicro())/1e6)
+}
+
+func AppendCbor(buf []byte, val any) ([]byte, error) {
+	switch v := val.(type) {
+	case nil:
+		return append(buf, cborSimple|22), nil
+	case bool:
+		if v {
+			return append(buf, cborSimple|21), nil
+		}
+		return append(buf, cborSimple|20), nil
+	case string:
+		return appendCborText(buf, v), nil
+	case []byte:
+		return append(appendCborHead(buf, cborBytes, uint64(len(v))), v...), nil
+	case int:
+		return appendCborInt(buf, int64(v)), nil
+	case int32:
+		return appendCborInt(buf, int64(v)), nil
+	case int64:
+		return appendCborInt(buf, v), nil
+	case uint32:
+		return appendCborHead(buf, cborUint, uint64(v)), nil
+	case uint64:
+		return appendCborHead(buf, cborUint, v), nil
+	case float32:
+		return appendCborFloat(buf, float64(v)), nil
+	case float64:
+		return appendCborFloat(buf, v), nil
+	case json.Number:
+		i, e := v.Int64()
+		if nil == e {
+			return appendCborInt(buf, i), nil
+		}
+		f, e := v.Float64()
+		return appendCborFloat(buf, f), e
+	case time.Time:
+		return appendCborTime(buf, v), nil
+	case Record:
+		buf = appendCborHead(buf, cborMap, uint64(len(v)))
+		for _, f := range v {
+			var e error
+			buf, e = AppendCbor(appendCborText(buf, f.Key), f.Value)
+			if nil != e {
+				return buf, e
+			}
+		}
+		return buf, nil
+	case map[string]any:
+		return appendCborMap(buf, v)
+	case map[string]int64:
+		return appendCborMap(buf, v)
+	case []string:
+		return appendCborArray(buf, v)
+	case []any:
+		return appendCborArray(buf, v)
+	default:
+		return buf, fmt.Errorf("%w: %T", ErrCborUnsupported, val)
+	}
+}
+
+func appendCborMap[V any](buf []byte, m map[string]V) ([]byte, error) {
+	buf = appendCborHead(buf, cborMap, uint64(len(m)))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		var e error
+		buf, e = AppendCbor(appendCborText(buf, key), m[key])
+		if nil != e {
+			return buf, e
+		}
+	}
+	return buf, nil
+}
+
+func appendCborArray[V any](buf []byte, items []V) ([]byte, error) {
+	buf = appendCborHead(buf, cborArray, uint64(len(items)))
+	for _, item := range items {
+		var e error
+		buf, e = AppendCbor(buf, item)
+		if nil != e {
+			return buf, e
+		}
+	}
+	return buf, nil
+}
+
+func RecordsToCborWriter(wtr io.Writer) func(RecordIter) error {
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		var buf []byte
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			buf, e = AppendCbor(buf[:0], rec)
+			if nil != e {
+				return e
+			}
+
+			_, e = bw.Write(buf)
+			if nil != e {
+				return e
+			}
+		}
+		return bw.Flush()
+	}
+}
+
+func (c FileTypeToString) BasicStatsToCborWriter(
+	wtr io.Writer,
+) func(iter.Seq2[BasicStat, error]) error {
+	return func(stats iter.Seq2[BasicStat, error]) error {
+		return RecordsToCborWriter(wtr)(c.EnrichedStatsToRecords(
+			BasicStatsToEnriched(stats),
+		))
+	}
+}
@@ -0,0 +1,80 @@
+package names2stats
+
+import (
+	"context"
+)
+
+type ChannelOptions struct {
+	Stat            FilenameToBasicStat
+	Buffer          int
+	ContinueOnError bool
+}
+
+func ChannelToNames(ctx context.Context, names <-chan string) NameIter {
+	return func(yield func(string, error) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				yield("", ctx.Err())
+				return
+			case name, ok := <-names:
+				if !ok {
+					return
+				}
+				if !yield(name, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func StatsChannel(
+	ctx context.Context,
+	names NameIter,
+	opts ChannelOptions,
+) (<-chan BasicStat, <-chan error) {
+	var stats chan BasicStat = make(chan BasicStat, opts.Buffer)
+	var errs chan error = make(chan error, max(1, opts.Buffer))
+
+	var sendErr func(error) bool = func(e error) bool {
+		select {
+		case errs <- e:
+			return opts.ContinueOnError
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(stats)
+
+		for name, e := range names {
+			if nil == e {
+				e = ctx.Err()
+			}
+			if nil != e {
+				sendErr(e)
+				return
+			}
+
+			s, e := opts.Stat(name)
+			if nil != e {
+				if !sendErr(e) {
+					return
+				}
+				continue
+			}
+
+			select {
+			case stats <- s:
+			case <-ctx.Done():
+				sendErr(ctx.Err())
+				return
+			}
+		}
+	}()
+
+	return stats, errs
+}
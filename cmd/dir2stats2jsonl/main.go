@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	ns "github.com/takanoriyanagitani/go-names2stats"
+	"github.com/takanoriyanagitani/go-names2stats/exitcode"
+	. "github.com/takanoriyanagitani/go-names2stats/util"
+)
+
+var envValByKey func(string) IO[string] = Lift(
+	func(key string) (string, error) {
+		val, found := os.LookupEnv(key)
+		switch found {
+		case true:
+			return val, nil
+		default:
+			return "", fmt.Errorf("env var %s missing", key)
+		}
+	},
+)
+
+func envOpt[T any](key string, parse func(string) (T, error), alt T) IO[T] {
+	return func(ctx context.Context) (T, error) {
+		_, found := os.LookupEnv(key)
+		switch found {
+		case true:
+			return Bind(envValByKey(key), Lift(parse))(ctx)
+		default:
+			return alt, nil
+		}
+	}
+}
+
+func envBool(key string, alt bool) IO[bool] {
+	return envOpt(key, strconv.ParseBool, alt)
+}
+
+type options struct {
+	root        ns.RootDirname
+	start       string
+	maxDepth    int
+	dirs        bool
+	concurrency int
+	inodeOrder  bool
+	typesOnly   bool
+	skipErrors  bool
+	fields      []string
+}
+
+var rootDirname IO[string] = envValByKey("ENV_ROOT_DIR_NAME")
+
+var walkStart IO[string] = envOpt(
+	"ENV_WALK_START",
+	func(s string) (string, error) { return s, nil },
+	".",
+)
+
+var maxDepth IO[int] = envOpt("ENV_MAX_DEPTH", strconv.Atoi, 0)
+
+var concurrency IO[int] = envOpt("ENV_WALK_CONCURRENCY", strconv.Atoi, 1)
+
+var outputFields IO[[]string] = envOpt(
+	"ENV_OUTPUT_FIELDS",
+	func(s string) ([]string, error) { return strings.Split(s, ","), nil },
+	nil,
+)
+
+var opts IO[options] = func(ctx context.Context) (options, error) {
+	var o options
+
+	root, e := rootDirname(ctx)
+	if nil != e {
+		return o, e
+	}
+	o.root = ns.RootDirname(root)
+
+	o.start, e = walkStart(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.maxDepth, e = maxDepth(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.dirs, e = envBool("ENV_WALK_DIRS", true)(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.concurrency, e = concurrency(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.inodeOrder, e = envBool("ENV_WALK_INODE_ORDER", false)(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.typesOnly, e = envBool("ENV_WALK_TYPES_ONLY", false)(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.skipErrors, e = envBool("ENV_WALK_SKIP_ERRORS", false)(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.fields, e = outputFields(ctx)
+	return o, e
+}
+
+func (o options) walker() ns.Walker {
+	var w ns.Walker = ns.Walker{
+		MaxDepth:    o.maxDepth,
+		Concurrency: o.concurrency,
+		InodeOrder:  o.inodeOrder,
+		TypesOnly:   o.typesOnly || !ns.FieldsNeedStat(o.fields),
+	}
+	if o.skipErrors {
+		w.OnError = func(name string, e error) error {
+			log.Printf("%s: %v\n", name, e)
+			return nil
+		}
+	}
+	return w
+}
+
+func (o options) run(ctx context.Context, rt ns.Root) error {
+	var stats iter.Seq2[ns.BasicStat, error] = iter.Seq2[ns.BasicStat, error](
+		o.walker().ToBasicStats(ctx, rt, o.start),
+	)
+	if !o.dirs {
+		var all iter.Seq2[ns.BasicStat, error] = stats
+		stats = func(yield func(ns.BasicStat, error) bool) {
+			for s, e := range all {
+				if nil == e && ns.FileTypeFldr == s.FileType {
+					continue
+				}
+				if !yield(s, e) {
+					return
+				}
+			}
+		}
+	}
+	if 0 == len(o.fields) {
+		return ns.FileTypeToStringDefault.BasicStatsToWriter(os.Stdout)(stats)
+	}
+
+	var records ns.RecordIter = ns.FileTypeToStringDefault.
+		EnrichedStatsToRecords(ns.BasicStatsToEnriched(stats)).
+		Map(ns.ProjectFields(o.fields))
+	return ns.RecordsToWriter(os.Stdout)(records)
+}
+
+func (o options) runOnRoot(ctx context.Context) error {
+	var opened bool
+	e := o.root.WithRoot(func(rt ns.Root) error {
+		opened = true
+		return o.run(ctx, rt)
+	})
+	if !opened {
+		return exitcode.Wrap(exitcode.RootOpen, e)
+	}
+	return e
+}
+
+var dir2stats2jsonl2stdout IO[Void] = Bind(
+	opts,
+	func(o options) IO[Void] {
+		return func(ctx context.Context) (Void, error) {
+			return Empty, o.runOnRoot(ctx)
+		}
+	},
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	_, e := dir2stats2jsonl2stdout(ctx)
+	if nil != ctx.Err() {
+		e = exitcode.Wrap(exitcode.Signal, errors.Join(e, ctx.Err()))
+	}
+	if nil != e {
+		log.Printf("%v\n", e)
+	}
+
+	cancel()
+	exitcode.Exit(e)
+}
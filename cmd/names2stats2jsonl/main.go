@@ -1,14 +1,30 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"io/fs"
 	"iter"
 	"log"
+	"net"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	ns "github.com/takanoriyanagitani/go-names2stats"
+	"github.com/takanoriyanagitani/go-names2stats/exitcode"
+	"github.com/takanoriyanagitani/go-names2stats/systemd"
 	. "github.com/takanoriyanagitani/go-names2stats/util"
+	"github.com/takanoriyanagitani/go-names2stats/wasm"
 )
 
 var envValByKey func(string) IO[string] = Lift(
@@ -23,27 +39,1680 @@ var envValByKey func(string) IO[string] = Lift(
 	},
 )
 
+func envOpt[T any](key string, parse func(string) (T, error), alt T) IO[T] {
+	return func(ctx context.Context) (T, error) {
+		_, found := os.LookupEnv(key)
+		switch found {
+		case true:
+			return Bind(envValByKey(key), Lift(parse))(ctx)
+		default:
+			return alt, nil
+		}
+	}
+}
+
+func envBool(key string) IO[bool] {
+	return envOpt(key, strconv.ParseBool, false)
+}
+
 var rootDirname IO[string] = envValByKey("ENV_ROOT_DIR_NAME")
 
+var longPaths IO[bool] = envOpt("ENV_WINDOWS_LONG_PATHS", strconv.ParseBool, true)
+
 var rdir IO[ns.RootDirname] = Bind(
 	rootDirname,
-	Lift(func(s string) (ns.RootDirname, error) {
-		return ns.RootDirname(s), nil
+	func(s string) IO[ns.RootDirname] {
+		return Bind(
+			longPaths,
+			Lift(func(extended bool) (ns.RootDirname, error) {
+				var d ns.RootDirname = ns.RootDirname(s)
+				if extended {
+					d = d.ToExtendedLength()
+				}
+				return d, nil
+			}),
+		)
+	},
+)
+
+var inputDelim IO[ns.NameDelim] = envOpt(
+	"ENV_INPUT_DELIM",
+	ns.ParseNameDelim,
+	ns.NameDelimNewline,
+)
+
+func strToInputPaths(s string) (ns.InputPaths, error) {
+	return ns.InputPathListToPaths(s), nil
+}
+
+var inputPaths IO[ns.InputPaths] = Bind(
+	All(
+		envOpt("ENV_INPUT_PATH", strToInputPaths, nil),
+		envOpt("ENV_NAMES_FILE", strToInputPaths, nil),
+	),
+	Lift(func(p []ns.InputPaths) (ns.InputPaths, error) {
+		return slices.Concat(p...), nil
 	}),
 )
 
-var filenames iter.Seq[string] = ns.StdinToNames()
+func strToCommand(s string) (ns.Command, error) {
+	return ns.CommandLineToCommand(s), nil
+}
+
+var inputCmd IO[ns.Command] = envOpt("ENV_INPUT_CMD", strToCommand, nil)
 
-var names2stats2jsonl2stdout IO[Void] = Bind(
-	rdir,
-	Lift(func(r ns.RootDirname) (Void, error) {
-		return Empty, r.NamesToBasicStatsToStdout(filenames)
+var pathSources IO[ns.NameSources] = Bind(
+	inputPaths,
+	func(p ns.InputPaths) IO[ns.NameSources] {
+		return Bind(
+			inputDelim,
+			Lift(func(d ns.NameDelim) (ns.NameSources, error) {
+				return p.ToSourcesDelim(d), nil
+			}),
+		)
+	},
+)
+
+var cmdSources IO[ns.NameSources] = Bind(
+	inputCmd,
+	func(c ns.Command) IO[ns.NameSources] {
+		if 0 == len(c) {
+			return Of(ns.NameSources(nil))
+		}
+		return Bind(
+			inputDelim,
+			func(d ns.NameDelim) IO[ns.NameSources] {
+				return func(ctx context.Context) (ns.NameSources, error) {
+					return ns.NameSources{{
+						Tag:   "exec:" + strings.Join(c, " "),
+						Names: c.ToNamesDelim(ctx, d),
+					}}, nil
+				}
+			},
+		)
+	},
+)
+
+func strToSpecs(s string) ([]string, error) {
+	var ret []string
+	for spec := range strings.SplitSeq(s, ";") {
+		if "" != strings.TrimSpace(spec) {
+			ret = append(ret, strings.TrimSpace(spec))
+		}
+	}
+	return ret, nil
+}
+
+var inputSources IO[[]string] = envOpt("ENV_INPUT_SOURCES", strToSpecs, nil)
+
+var prioritySpecs IO[[]string] = envOpt("ENV_INPUT_PRIORITY_SOURCES", strToSpecs, nil)
+
+var globSpecs IO[[]string] = envOpt(
+	"ENV_INPUT_GLOBS",
+	func(s string) ([]string, error) {
+		patterns, e := strToSpecs(s)
+		for i, p := range patterns {
+			patterns[i] = "glob:" + p
+		}
+		return patterns, e
+	},
+	nil,
+)
+
+var tlsOptions IO[ns.TLSOptions] = Bind(
+	All(
+		envOpt(
+			"ENV_TLS_CA",
+			func(s string) (string, error) { return s, nil },
+			"",
+		),
+		envOpt(
+			"ENV_TLS_CERT",
+			func(s string) (string, error) { return s, nil },
+			"",
+		),
+		envOpt(
+			"ENV_TLS_KEY",
+			func(s string) (string, error) { return s, nil },
+			"",
+		),
+	),
+	func(files []string) IO[ns.TLSOptions] {
+		return Bind(
+			envOpt("ENV_TLS_MIN_VERSION", ns.ParseTLSVersion, 0),
+			Lift(func(v uint16) (ns.TLSOptions, error) {
+				return ns.TLSOptions{
+					CAFile:     files[0],
+					CertFile:   files[1],
+					KeyFile:    files[2],
+					MinVersion: v,
+				}, nil
+			}),
+		)
+	},
+)
+
+var socketIdleTimeout IO[time.Duration] = envOpt(
+	"ENV_SOCKET_IDLE_TIMEOUT",
+	time.ParseDuration,
+	ns.SocketIdleTimeoutDefault,
+)
+
+func socketSource(d ns.NameDelim) IO[ns.SourceFactory] {
+	return Bind(
+		socketIdleTimeout,
+		func(idle time.Duration) IO[ns.SourceFactory] {
+			return Bind(
+				tlsOptions,
+				Lift(func(o ns.TLSOptions) (ns.SourceFactory, error) {
+					var s ns.SocketSource = ns.SocketSource{
+						Delim:       d,
+						IdleTimeout: idle,
+						Rejected: func(addr net.Addr, e error) {
+							log.Printf("socket: rejected %v: %v\n", addr, e)
+						},
+					}
+					if !o.IsEmpty() {
+						conf, e := o.ServerConfig()
+						if nil != e {
+							return nil, e
+						}
+						s.TLS = conf
+					}
+					return s.Factory(), nil
+				}),
+			)
+		},
+	)
+}
+
+func openSpecs(fsys fs.FS, specIO IO[[]string]) IO[ns.NameSources] {
+	return Bind(specIO, func(specs []string) IO[ns.NameSources] {
+		if 0 == len(specs) {
+			return Of(ns.NameSources(nil))
+		}
+		return Bind(
+			inputDelim,
+			func(d ns.NameDelim) IO[ns.NameSources] {
+				return Bind(
+					socketSource(d),
+					func(socket ns.SourceFactory) IO[ns.NameSources] {
+						return Bind(
+							pathCase,
+							func(c ns.PathCase) IO[ns.NameSources] {
+								return func(ctx context.Context) (ns.NameSources, error) {
+									return ns.DefaultSources(fsys, d).
+										Register("glob", ns.GlobSourceFactory(fsys, c)).
+										Register("socket", socket).
+										OpenAll(ctx, specs)
+								}
+							},
+						)
+					},
+				)
+			},
+		)
+	})
+}
+
+var allSpecs IO[[]string] = Bind(
+	All(inputSources, globSpecs),
+	Lift(func(specs [][]string) ([]string, error) {
+		return slices.Concat(specs...), nil
 	}),
 )
 
-func main() {
-	_, e := names2stats2jsonl2stdout(context.Background())
+var stdinSource IO[ns.NameSource] = Bind(
+	inputDelim,
+	Lift(func(d ns.NameDelim) (ns.NameSource, error) {
+		return ns.NameSource{
+			Tag:   "stdin",
+			Names: d.ReaderToNameIter(os.Stdin),
+		}, nil
+	}),
+)
+
+var stdinEnabled IO[bool] = envBool("ENV_INPUT_STDIN")
+
+func sourcesIn(fsys fs.FS) IO[ns.NameSources] {
+	return Bind(
+		All(pathSources, cmdSources, openSpecs(fsys, allSpecs)),
+		func(s []ns.NameSources) IO[ns.NameSources] {
+			var merged ns.NameSources = slices.Concat(s...)
+			return Bind(
+				stdinEnabled,
+				func(stdin bool) IO[ns.NameSources] {
+					if !stdin && 0 < len(merged) {
+						return Of(merged)
+					}
+					return Bind(
+						stdinSource,
+						Lift(func(src ns.NameSource) (ns.NameSources, error) {
+							return append(merged, src), nil
+						}),
+					)
+				},
+			)
+		},
+	)
+}
+
+func filenamesIn(fsys fs.FS) IO[ns.TaggedNameIter] {
+	return Bind(
+		openSpecs(fsys, prioritySpecs),
+		func(priority ns.NameSources) IO[ns.TaggedNameIter] {
+			return Bind(
+				sourcesIn(fsys),
+				Lift(func(s ns.NameSources) (ns.TaggedNameIter, error) {
+					return priority.PriorityTagged(s), nil
+				}),
+			)
+		},
+	)
+}
+
+var globMode IO[bool] = envBool("ENV_INPUT_GLOB")
+
+var sortMode IO[bool] = envBool("ENV_OUTPUT_SORT")
+
+var pathCase IO[ns.PathCase] = Bind(
+	envBool("ENV_CASE_INSENSITIVE"),
+	Lift(func(insensitive bool) (ns.PathCase, error) {
+		switch insensitive {
+		case true:
+			return ns.PathCaseInsensitive, nil
+		default:
+			return ns.PathCaseSensitive, nil
+		}
+	}),
+)
+
+var dedupeInode IO[bool] = envBool("ENV_DEDUPE_INODE")
+
+var staleDays IO[float64] = envOpt(
+	"ENV_STALE_DAYS",
+	func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	},
+	0,
+)
+
+var topN IO[int] = envOpt("ENV_TOP_N", strconv.Atoi, 0)
+
+var reportConfig IO[ns.ReportConfig] = func(
+	ctx context.Context,
+) (c ns.ReportConfig, e error) {
+	c.Now = time.Now()
+
+	days, e := staleDays(ctx)
+	if nil != e {
+		return c, e
+	}
+	c.StaleAge = time.Duration(days * float64(24*time.Hour))
+
+	c.Top, e = topN(ctx)
+	return c, e
+}
+
+var reportPreset IO[ns.Report] = Bind(
+	reportConfig,
+	func(cfg ns.ReportConfig) IO[ns.Report] {
+		return envOpt("ENV_PRESET", cfg.ParseReportPreset, nil)
+	},
+)
+
+var quotaLimits IO[[]ns.Quota] = envOpt("ENV_QUOTAS", ns.ParseQuotas, nil)
+
+var ownerFilter IO[ns.OwnerFilter] = Bind(
+	All(
+		envOpt("ENV_OWNER_USERS", func(s string) (string, error) { return s, nil }, ""),
+		envOpt("ENV_OWNER_GROUPS", func(s string) (string, error) { return s, nil }, ""),
+	),
+	Lift(func(lists []string) (ns.OwnerFilter, error) {
+		return ns.ParseOwnerFilter(lists[0], lists[1])
+	}),
+)
+
+var bloomFpRate IO[float64] = envOpt(
+	"ENV_DEDUPE_BLOOM_FP_RATE",
+	func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	},
+	0.01,
+)
+
+var bloomCapacity IO[uint64] = envOpt(
+	"ENV_DEDUPE_BLOOM_CAPACITY",
+	func(s string) (uint64, error) {
+		return strconv.ParseUint(s, 10, 64)
+	},
+	0,
+)
+
+var bloomFilter IO[*ns.BloomFilter] = Bind(
+	bloomCapacity,
+	func(capacity uint64) IO[*ns.BloomFilter] {
+		if 0 == capacity {
+			return Of[*ns.BloomFilter](nil)
+		}
+		return Bind(
+			bloomFpRate,
+			Lift(func(rate float64) (*ns.BloomFilter, error) {
+				return ns.NewBloomFilter(capacity, rate)
+			}),
+		)
+	},
+)
+
+var adaptiveConcurrency IO[*ns.AdaptiveConcurrency] = Bind(
+	envBool("ENV_CONCURRENCY_ADAPTIVE"),
+	func(enabled bool) IO[*ns.AdaptiveConcurrency] {
+		if !enabled {
+			return Of[*ns.AdaptiveConcurrency](nil)
+		}
+		return Bind(
+			All(
+				envOpt("ENV_CONCURRENCY_MIN", strconv.Atoi, 1),
+				envOpt("ENV_CONCURRENCY_MAX", strconv.Atoi, ns.AdaptiveMaxDefault),
+			),
+			func(limits []int) IO[*ns.AdaptiveConcurrency] {
+				return Bind(
+					envOpt(
+						"ENV_CONCURRENCY_TARGET_LATENCY",
+						time.ParseDuration,
+						ns.AdaptiveTargetDefault,
+					),
+					func(target time.Duration) IO[*ns.AdaptiveConcurrency] {
+						return Bind(
+							envBool("ENV_CONCURRENCY_NO_SLAB"),
+							Lift(func(noSlab bool) (*ns.AdaptiveConcurrency, error) {
+								return &ns.AdaptiveConcurrency{
+									Min:    limits[0],
+									Max:    limits[1],
+									Target: target,
+									NoSlab: noSlab,
+								}, nil
+							}),
+						)
+					},
+				)
+			},
+		)
+	},
+)
+
+var enrichers IO[ns.Enrichers] = envOpt(
+	"ENV_ENRICHERS",
+	func(s string) (ns.Enrichers, error) {
+		return ns.EnricherRegistryDefault.Lookup(strings.Split(s, ",")...)
+	},
+	nil,
+)
+
+var execEnrichers IO[ns.Enrichers] = Bind(
+	All(
+		envOpt("ENV_ENRICH_EXEC", strToCommand, nil),
+		envOpt("ENV_ENRICH_EXEC_STREAM", strToCommand, nil),
+	),
+	Lift(func(c []ns.Command) (ns.Enrichers, error) {
+		var ret ns.Enrichers
+		if 0 < len(c[0]) {
+			ret = append(ret, ns.ExecEnricher{Command: c[0]})
+		}
+		if 0 < len(c[1]) {
+			ret = append(ret, &ns.ExecStreamEnricher{Command: c[1]})
+		}
+		return ret, nil
+	}),
+)
+
+var wasmEnrichers IO[ns.Enrichers] = Bind(
+	envOpt("ENV_ENRICH_WASM", strToInputPaths, nil),
+	func(paths ns.InputPaths) IO[ns.Enrichers] {
+		return func(ctx context.Context) (ns.Enrichers, error) {
+			var ret ns.Enrichers
+			for _, p := range paths {
+				w, e := wasm.NewEnricherFromFile(ctx, string(p))
+				if nil != e {
+					return nil, errors.Join(e, ret.Close())
+				}
+				ret = append(ret, w)
+			}
+			return ret, nil
+		}
+	},
+)
+
+var computedFields IO[ns.Enrichers] = envOpt(
+	"ENV_COMPUTED_FIELDS",
+	func(s string) (ns.Enrichers, error) {
+		fields, e := ns.ParseComputedFields(s)
+		if nil != e || 0 == len(fields) {
+			return nil, e
+		}
+		return ns.Enrichers{ns.ComputedFields{
+			Fields: fields,
+			Now:    time.Now(),
+		}}, nil
+	},
+	nil,
+)
+
+var emptyPolicy IO[ns.EmptyPolicy] = envOpt(
+	"ENV_EMPTY_POLICY",
+	ns.ParseEmptyPolicy,
+	ns.EmptyAsZero,
+)
+
+var keyCase IO[ns.KeyCase] = envOpt(
+	"ENV_KEY_CASE",
+	ns.ParseKeyCase,
+	ns.KeyCaseAsIs,
+)
+
+var headerEnabled IO[bool] = envBool("ENV_HEADER")
+
+var trailerEnabled IO[bool] = envBool("ENV_TRAILER")
+
+var heartbeatInterval IO[time.Duration] = envOpt(
+	"ENV_HEARTBEAT_INTERVAL",
+	time.ParseDuration,
+	0,
+)
+
+func envSettings() []string {
+	var ret []string
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "ENV_") {
+			ret = append(ret, kv)
+		}
+	}
+	return ret
+}
+
+func recordHost(val string) (string, error) {
+	switch val {
+	case "auto":
+		return os.Hostname()
+	default:
+		return val, nil
+	}
+}
+
+func recordRoot(val string) (string, error) {
+	switch val {
+	case "auto":
+		root, e := rootDirname(context.Background())
+		if nil != e {
+			return "", e
+		}
+		return filepath.Abs(root)
+	default:
+		return val, nil
+	}
+}
+
+var annotations IO[ns.Record] = Bind(
+	All(
+		envOpt("ENV_RECORD_HOST", recordHost, ""),
+		envOpt("ENV_RECORD_ROOT", recordRoot, ""),
+	),
+	func(vals []string) IO[ns.Record] {
+		var ret ns.Record
+		for i, key := range []string{"host", "root"} {
+			if "" != vals[i] {
+				ret = append(ret, ns.Field{Key: key, Value: vals[i]})
+			}
+		}
+		return Bind(
+			envBool("ENV_RECORD_SCHEMA_VERSION"),
+			Lift(func(versioned bool) (ns.Record, error) {
+				if versioned {
+					ret = append(ret, ns.Field{
+						Key:   "schema_version",
+						Value: ns.SchemaVersionCurrent,
+					})
+				}
+				return ret, nil
+			}),
+		)
+	},
+)
+
+var labels IO[ns.Record] = envOpt("ENV_LABELS", ns.ParseLabels, nil)
+
+var validator IO[*ns.NameValidator] = envOpt(
+	"ENV_INPUT_VALIDATE",
+	func(s string) (*ns.NameValidator, error) {
+		policy, e := ns.ParseValidationPolicy(s)
+		if nil != e {
+			return nil, e
+		}
+		return &ns.NameValidator{
+			Policy: policy,
+			Warn: func(t ns.TaggedName, v ns.Violation) {
+				log.Printf("%s: %s name: %q\n", t.Origin, v, t.Name)
+			},
+		}, nil
+	},
+	nil,
+)
+
+type options struct {
+	root        ns.RootDirname
+	names       ns.TaggedNameIter
+	sourceFS    *ns.DeferredFS
+	glob        bool
+	sorted      bool
+	pathCase    ns.PathCase
+	once        bool
+	owner       ns.OwnerFilter
+	report      ns.Report
+	quota       *ns.QuotaCheck
+	adaptive    *ns.AdaptiveConcurrency
+	bloom       *ns.BloomFilter
+	enrichers   ns.Enrichers
+	empty       ns.EmptyPolicy
+	keyCase     ns.KeyCase
+	renames     ns.KeyRenames
+	header      bool
+	trailer     bool
+	heartbeat   time.Duration
+	human       bool
+	format      string
+	columns     []string
+	fields      []string
+	noHeader    bool
+	outputFiles []string
+	scannedAt   bool
+
+	measurement string
+	forwardTag  string
+	batchRows   int
+	avroCodec   string
+	sqliteDb    string
+	sqliteCmd   ns.Command
+	sqliteBatch int
+	jsonIndent  string
+	jsonNoEsc   bool
+	jsonCanon   bool
+	xmlRoot     string
+	xmlElement  string
+	template    ns.RecordTemplate
+	compression string
+	tableBatch  int
+	rotation    ns.Rotation
+	asn1Layout  ns.Asn1Layout
+	tee         []ns.TeeTarget
+	dryRun      bool
+	dryRunEvery int
+
+	annotations ns.Record
+	validator   *ns.NameValidator
+}
+
+var opts IO[options] = func(ctx context.Context) (o options, e error) {
+	o.root, e = rdir(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.sourceFS = &ns.DeferredFS{}
+	o.names, e = filenamesIn(o.sourceFS)(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.glob, e = globMode(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.sorted, e = sortMode(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.pathCase, e = pathCase(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.once, e = dedupeInode(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.owner, e = ownerFilter(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.report, e = reportPreset(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	quotas, e := quotaLimits(ctx)
+	if nil != e {
+		return o, e
+	}
+	if 0 < len(quotas) {
+		o.quota = ns.NewQuotaCheck(quotas, o.pathCase)
+	}
+
+	o.bloom, e = bloomFilter(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.adaptive, e = adaptiveConcurrency(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.enrichers, e = enrichers(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	execs, e := execEnrichers(ctx)
+	if nil != e {
+		return o, e
+	}
+	o.enrichers = append(o.enrichers, execs...)
+
+	wasms, e := wasmEnrichers(ctx)
+	if nil != e {
+		return o, e
+	}
+	o.enrichers = append(o.enrichers, wasms...)
+
+	computed, e := computedFields(ctx)
+	if nil != e {
+		return o, e
+	}
+	o.enrichers = append(o.enrichers, computed...)
+
+	enricher, ok := o.report.(ns.Enricher)
+	if ok {
+		o.enrichers = append(o.enrichers, enricher)
+	}
+
+	o.empty, e = emptyPolicy(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.keyCase, e = keyCase(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.renames, e = keyRenames(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.header, e = headerEnabled(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.trailer, e = trailerEnabled(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.heartbeat, e = heartbeatInterval(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.human, e = humanOutput(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.format, e = outputFormat(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	if "ls" == o.format {
+		o.enrichers = append(o.enrichers, ns.EnricherMode)
+	}
+
+	if "template" == o.format {
+		o.template, e = outputTemplate(ctx)
+		if nil != e {
+			return o, e
+		}
+	}
+
+	o.columns, e = outputColumns(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.fields, e = outputFields(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.compression, e = outputCompression(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.tableBatch, e = tableBatchRows(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.rotation, e = outputRotation(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.dryRun, e = dryRun(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.dryRunEvery, e = dryRunSampleEvery(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.noHeader, e = outputNoHeader(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.outputFiles, e = outputFiles(ctx)
+	if nil != e {
+		return o, e
+	}
+	if "" != o.rotation.Pattern && 0 < len(o.outputFiles) {
+		return o, ErrRotateWithFiles
+	}
+	if 0 < o.rotation.MaxBytes && slices.Contains(blockFormats, o.format) {
+		return o, fmt.Errorf("%w: %s", ErrRotateBytesFormat, o.format)
+	}
+
+	o.tee, e = outputTee(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.asn1Layout, e = asn1Layout(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.scannedAt, e = scannedAt(ctx)
 	if nil != e {
-		log.Printf("%v\n", e)
+		return o, e
 	}
+
+	o.measurement, e = influxMeasurement(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.forwardTag, e = msgpackForwardTag(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.batchRows, e = arrowBatchRows(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.avroCodec, e = avroCodec(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.sqliteDb, e = sqliteDb(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.sqliteCmd, e = sqliteCmd(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.sqliteBatch, e = sqliteBatchRows(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.jsonIndent, e = jsonIndent(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.jsonNoEsc, e = jsonNoHTMLEscape(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.jsonCanon, e = jsonCanonical(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.xmlRoot, e = xmlRoot(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.xmlElement, e = xmlElement(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	o.annotations, e = annotations(ctx)
+	if nil != e {
+		return o, e
+	}
+
+	static, e := labels(ctx)
+	if nil != e {
+		return o, e
+	}
+	for _, f := range static {
+		o.annotations = o.annotations.Set(f.Key, f.Value)
+	}
+
+	o.validator, e = validator(ctx)
+	return o, e
+}
+
+func (o options) errorCounts(fatal error) map[string]int64 {
+	var ret map[string]int64 = map[string]int64{}
+	if nil != o.validator {
+		for v, n := range o.validator.Counts() {
+			if ns.ActionAllow != o.validator.Policy[v] {
+				ret["invalid_name_"+v.String()] = int64(n)
+			}
+		}
+	}
+	if nil != fatal && !errors.Is(fatal, ns.ErrInvalidName) {
+		ret["fatal"]++
+	}
+	return ret
+}
+
+func (o options) run(ctx context.Context, rt ns.Root) error {
+	var start time.Time = time.Now()
+
+	var names ns.TaggedNameIter = o.names
+	if nil != o.validator {
+		names = o.validator.Validate(names)
+		defer func() {
+			log.Printf("validation: %s\n", o.validator.Summary())
+		}()
+	}
+
+	if o.glob {
+		names = rt.ExpandTaggedGlobsCase(names, o.pathCase)
+	}
+
+	if nil != o.bloom {
+		names = names.Filter(o.bloom.FirstSeenCase(o.pathCase))
+	}
+
+	var checks []ns.InfoCheck
+	if !o.owner.IsEmpty() {
+		checks = append(checks, o.owner.Check)
+	}
+	if o.once {
+		checks = append(checks, ns.NewOnceCheck())
+	}
+	var n2s ns.FilenameToBasicStat = rt.ToFilenameToBasicStatChecked(checks...)
+	if o.dryRun {
+		return o.estimate(ctx, rt, n2s, names)
+	}
+
+	var statted iter.Seq2[ns.BasicStat, error] = n2s.TaggedToBasicStats(names)
+	if nil != o.adaptive {
+		statted = o.adaptive.TaggedToBasicStats(n2s, names)
+	}
+
+	var stats ns.BasicStatIter = ns.BasicStatIter(statted).
+		SkipErr(ns.ErrAlreadySeen).
+		SkipErr(ns.ErrNotOwned)
+	if o.sorted {
+		stats = stats.CanonicalCase(o.pathCase)
+	}
+
+	var seq iter.Seq2[ns.BasicStat, error] = iter.Seq2[ns.BasicStat, error](
+		stats,
+	)
+
+	if nil != o.report {
+		seq = o.report.Filter(rt, seq)
+	}
+
+	if nil != o.quota {
+		seq = o.quota.Tally(seq)
+	}
+
+	var totals ns.StreamTotals
+	if o.trailer {
+		seq = totals.Tally(seq, ns.FileTypeToStringDefault)
+	}
+
+	var enriched iter.Seq2[ns.EnrichedStat, error] = ns.BasicStatsToEnriched(seq)
+	var active ns.Enrichers = o.enrichers.Project(o.fields)
+	if 0 < len(active) {
+		enriched = active.EnrichAll(ctx, rt, seq)
+	}
+
+	var records ns.RecordIter = ns.FileTypeToStringDefault.
+		EnrichedStatsToRecords(enriched)
+	if 0 < len(o.annotations) {
+		records = records.Map(ns.AppendFields(o.annotations))
+	}
+	if o.scannedAt {
+		records = records.Map(ns.ScannedAt(time.Now))
+	}
+	if nil != o.report {
+		records = records.AppendAll(o.report.Summary)
+	}
+	if nil != o.quota {
+		records = records.AppendAll(o.quota.Alerts)
+	}
+	if o.trailer {
+		records = records.WithTrailer(func(n int64, fatal error) ns.Record {
+			return ns.NewRunTrailer(
+				start,
+				n,
+				nil == fatal,
+				o.errorCounts(fatal),
+				&totals,
+			).ToRecord()
+		})
+	}
+	if o.header {
+		records = records.Prepend(ns.NewSnapshotHeader(
+			string(o.root),
+			envSettings(),
+		).ToRecord())
+	}
+	if 0 < o.heartbeat {
+		records = records.WithHeartbeat(ctx, o.heartbeat)
+	}
+
+	var out io.Writer = os.Stdout
+	var txns []ns.Transaction
+	if 0 < len(o.outputFiles) {
+		staged, e := ns.StageFiles(o.outputFiles)
+		if nil != e {
+			return exitcode.Wrap(exitcode.Sink, e)
+		}
+		out, txns = staged, append(txns, staged)
+	}
+
+	var write func(ns.RecordIter) error = o.output(ctx, out)
+	if "" != o.rotation.Pattern {
+		write = o.rotation.RecordsToFiles(func(w io.Writer) func(ns.RecordIter) error {
+			return o.output(ctx, w)
+		})
+	}
+
+	if 0 < len(o.tee) {
+		var sinks []func(ns.RecordIter) error = []func(ns.RecordIter) error{write}
+		for _, t := range o.tee {
+			f, e := ns.StageFile(t.Path)
+			if nil != e {
+				for _, txn := range txns {
+					e = errors.Join(e, txn.Abort(e))
+				}
+				return exitcode.Wrap(exitcode.Sink, e)
+			}
+			txns = append(txns, f)
+
+			var to options = o
+			to.format = t.Format
+			sinks = append(sinks, to.output(ctx, f))
+		}
+		write = ns.TeeRecords(sinks...)
+	}
+
+	e := write(records)
+	for _, txn := range txns {
+		switch e {
+		case nil:
+			e = exitcode.Wrap(exitcode.Sink, txn.Commit())
+		default:
+			e = errors.Join(e, txn.Abort(e))
+		}
+	}
+
+	var oe ns.OriginError
+	if errors.As(e, &oe) {
+		e = exitcode.Wrap(exitcode.Partial, e)
+	}
+	if nil == e && nil != o.quota {
+		e = exitcode.Wrap(exitcode.Quota, o.quota.Err())
+	}
+
+	return errors.Join(e, o.enrichers.Close())
+}
+
+func (o options) estimate(
+	ctx context.Context,
+	rt ns.Root,
+	n2s ns.FilenameToBasicStat,
+	names ns.TaggedNameIter,
+) error {
+	est, e := ns.DryRun{
+		SampleEvery: o.dryRunEvery,
+		Stat:        n2s,
+		Enrich: func(s ns.BasicStat) error {
+			_, e := o.enrichers.Enrich(ctx, rt, s)
+			return e
+		},
+	}.Estimate(ctx, names)
+	if nil != e {
+		return errors.Join(e, o.enrichers.Close())
+	}
+
+	var rec ns.Record = est.ToRecord()
+	return errors.Join(
+		ns.RecordsToWriter(sinkWriter{os.Stdout})(func(yield func(ns.Record, error) bool) {
+			yield(rec, nil)
+		}),
+		o.enrichers.Close(),
+	)
+}
+
+func (o options) output(ctx context.Context, out io.Writer) func(ns.RecordIter) error {
+	var human bool = o.human && io.Writer(os.Stdout) == out
+	return func(records ns.RecordIter) error {
+		compressed, e := ns.CompressWriter(out, o.compression)
+		if nil != e {
+			return exitcode.Wrap(exitcode.Sink, e)
+		}
+
+		var stdout io.Writer = sinkWriter{compressed}
+		var sink func(ns.RecordIter) error = ns.RecordsToWriter(stdout)
+		factory, statSink := o.sinks()[o.format]
+		switch {
+		case "" == o.format && human:
+			sink = ns.HumanRenderer{Color: ns.ColorAllowed()}.RecordsToWriter(stdout)
+		case "ls" == o.format:
+			sink = ns.LsRenderer{HumanSize: human}.RecordsToWriter(stdout)
+		case "influx" == o.format:
+			sink = ns.InfluxOptions{
+				Measurement: o.measurement,
+				Tags:        o.annotations.Keys(),
+			}.RecordsToWriter(stdout)
+		case "openmetrics" == o.format:
+			sink = ns.MetricsOptions{Labels: o.annotations}.RecordsToWriter(stdout)
+		case "protobuf" == o.format:
+			sink = ns.FileTypeToStringMapDefault.RecordsToProtoWriter(stdout)
+		case statSink:
+			sink = func(records ns.RecordIter) error {
+				return factory.BasicStatsToWriter(ctx, stdout)(
+					ns.StringToFileTypeDefault.RecordsToBasicStats(records),
+				)
+			}
+		default:
+			if 0 < len(o.fields) {
+				records = records.Map(ns.ProjectFields(o.fields))
+			}
+			records = records.
+				Map(o.empty.Apply).
+				Map(o.renames.Apply(o.keyCase))
+		}
+
+		switch o.format {
+		case "csv":
+			sink = ns.CsvOptions{
+				Columns:  o.columns,
+				NoHeader: o.noHeader,
+				CRLF:     true,
+			}.RecordsToWriter(stdout)
+		case "tsv":
+			sink = ns.TsvOptions{
+				Columns:  o.columns,
+				NoHeader: o.noHeader,
+			}.RecordsToWriter(stdout)
+		case "cbor":
+			sink = ns.RecordsToCborWriter(stdout)
+		case "table":
+			sink = ns.TableOptions{
+				Columns:   o.columns,
+				NoHeader:  o.noHeader,
+				BatchRows: o.tableBatch,
+			}.RecordsToWriter(stdout)
+		case "yaml":
+			sink = ns.RecordsToYamlWriter(stdout)
+		case "template":
+			sink = o.template.RecordsToWriter(stdout)
+		case "xml":
+			sink = ns.XmlOptions{
+				Root:    o.xmlRoot,
+				Element: o.xmlElement,
+			}.RecordsToWriter(stdout)
+		case "msgpack":
+			sink = ns.MsgpackOptions{ForwardTag: o.forwardTag}.RecordsToWriter(stdout)
+		case "", "jsonl":
+			if !human || "" != o.format {
+				sink = ns.JsonOptions{
+					Indent:       o.jsonIndent,
+					NoHTMLEscape: o.jsonNoEsc,
+					Canonical:    o.jsonCanon,
+					Flush:        0 < o.heartbeat || 0 < o.rotation.MaxBytes,
+				}.RecordsToWriter(stdout)
+			}
+		}
+
+		e = sink(records)
+		ce := compressed.Close()
+		if nil == e {
+			e = exitcode.Wrap(exitcode.Sink, ce)
+		}
+		return e
+	}
+}
+
+func (o options) sinks() ns.SinkRegistry {
+	var c ns.FileTypeToString = ns.FileTypeToStringDefault
+	var sql ns.SqliteOptions = ns.SqliteOptions{BatchRows: o.sqliteBatch}
+	var script ns.SinkFactory = sql.Sink()
+	if "" != o.sqliteDb {
+		script = o.sqliteCommandSink(sql)
+	}
+	return c.DefaultSinks().
+		Register("arrow", c.ArrowSink(o.batchRows, false)).
+		Register("feather", c.ArrowSink(o.batchRows, true)).
+		Register("avro", c.AvroSink(o.avroCodec)).
+		Register("ber", o.asn1Layout.BerSink()).
+		Register("ncdu", ns.NcduOptions{Root: string(o.root)}.Sink()).
+		Register("sql", script)
+}
+
+func (o options) sqliteCommandSink(sql ns.SqliteOptions) ns.SinkFactory {
+	var cmd ns.Command = append(slices.Clone(o.sqliteCmd), o.sqliteDb)
+	return func(ctx context.Context, _ io.Writer) (ns.Sink, error) {
+		stdin, e := cmd.StdinWriter(ctx)
+		if nil != e {
+			return nil, e
+		}
+
+		var w *ns.SqliteWriter = sql.NewWriter(sinkWriter{stdin})
+		return ns.StatSink{
+			Stat: w.Write,
+			Done: func() error { return errors.Join(w.Close(), stdin.Close()) },
+			Fail: func(cause error) error {
+				return errors.Join(w.Abort(cause), stdin.Close())
+			},
+		}, nil
+	}
+}
+
+type sinkWriter struct{ io.Writer }
+
+func (w sinkWriter) Write(p []byte) (int, error) {
+	n, e := w.Writer.Write(p)
+	return n, exitcode.Wrap(exitcode.Sink, e)
+}
+
+func (o options) runOnRoot(ctx context.Context) error {
+	var opened bool
+	e := o.root.WithRoot(func(rt ns.Root) error {
+		opened = true
+		o.sourceFS.Set(rt.ToFS())
+		_, ne := systemd.Notify(systemd.StateReady)
+		if nil != ne {
+			log.Printf("sd_notify: %v\n", ne)
+		}
+		defer func() { _, _ = systemd.Notify(systemd.StateStopping) }()
+		return o.run(ctx, rt)
+	})
+	if !opened {
+		return exitcode.Wrap(exitcode.RootOpen, e)
+	}
+	return e
+}
+
+var humanOutput IO[bool] = envOpt(
+	"ENV_OUTPUT_HUMAN",
+	func(s string) (bool, error) {
+		switch s {
+		case "auto":
+			return ns.IsTerminal(os.Stdout), nil
+		default:
+			return strconv.ParseBool(s)
+		}
+	},
+	ns.IsTerminal(os.Stdout),
+)
+
+var ErrUnknownFormat error = errors.New("unknown output format")
+
+var outputFormats []string = append(
+	[]string{
+		"jsonl", "csv", "tsv", "cbor", "msgpack", "influx", "openmetrics",
+		"protobuf", "yaml", "xml", "template", "ls", "table",
+	},
+	ns.FileTypeToStringDefault.DefaultSinks().Names()...,
+)
+
+var jsonIndent IO[string] = envOpt(
+	"ENV_JSON_INDENT",
+	func(s string) (string, error) {
+		switch s {
+		case "tab", "\t":
+			return "\t", nil
+		default:
+			n, e := strconv.Atoi(s)
+			if nil != e || n < 0 {
+				return "", fmt.Errorf("invalid indent %q: want spaces count or tab", s)
+			}
+			return strings.Repeat(" ", n), nil
+		}
+	},
+	"",
+)
+
+var ErrMissingTemplate error = errors.New(
+	"template output needs ENV_OUTPUT_TEMPLATE or ENV_OUTPUT_TEMPLATE_FILE",
+)
+
+var templateText IO[string] = Bind(
+	envOpt(
+		"ENV_OUTPUT_TEMPLATE_FILE",
+		func(s string) (string, error) {
+			raw, e := os.ReadFile(s)
+			return string(raw), e
+		},
+		"",
+	),
+	func(text string) IO[string] {
+		if "" != text {
+			return Of(text)
+		}
+		return envOpt(
+			"ENV_OUTPUT_TEMPLATE",
+			func(s string) (string, error) { return s, nil },
+			"",
+		)
+	},
+)
+
+var outputTemplate IO[ns.RecordTemplate] = Bind(
+	templateText,
+	Lift(func(text string) (ns.RecordTemplate, error) {
+		if "" == text {
+			return ns.RecordTemplate{}, ErrMissingTemplate
+		}
+		return ns.ParseRecordTemplate(text)
+	}),
+)
+
+var xmlRoot IO[string] = envOpt("ENV_XML_ROOT", ns.ParseXmlName, ns.XmlRootDefault)
+
+var xmlElement IO[string] = envOpt(
+	"ENV_XML_ELEMENT",
+	ns.ParseXmlName,
+	ns.XmlElementDefault,
+)
+
+var jsonNoHTMLEscape IO[bool] = envBool("ENV_JSON_NO_HTML_ESCAPE")
+
+var jsonCanonical IO[bool] = envBool("ENV_JSON_CANONICAL")
+
+var sqliteDb IO[string] = envOpt(
+	"ENV_SQLITE_DB",
+	func(s string) (string, error) { return s, nil },
+	"",
+)
+
+var sqliteCmd IO[ns.Command] = envOpt(
+	"ENV_SQLITE_CMD",
+	strToCommand,
+	ns.Command{"sqlite3", "-bail"},
+)
+
+var sqliteBatchRows IO[int] = envOpt(
+	"ENV_SQLITE_BATCH_ROWS",
+	strconv.Atoi,
+	ns.SqliteBatchRowsDefault,
+)
+
+var avroCodec IO[string] = envOpt(
+	"ENV_AVRO_CODEC",
+	ns.ParseAvroCodec,
+	ns.AvroCodecNull,
+)
+
+var arrowBatchRows IO[int] = envOpt("ENV_ARROW_BATCH_ROWS", strconv.Atoi, 0)
+
+var influxMeasurement IO[string] = envOpt(
+	"ENV_INFLUX_MEASUREMENT",
+	func(s string) (string, error) { return s, nil },
+	ns.MeasurementDefault,
+)
+
+var msgpackForwardTag IO[string] = envOpt(
+	"ENV_MSGPACK_FORWARD_TAG",
+	func(s string) (string, error) { return s, nil },
+	"",
+)
+
+var scannedAt IO[bool] = envBool("ENV_RECORD_SCANNED_AT")
+
+var outputColumns IO[[]string] = envOpt(
+	"ENV_OUTPUT_COLUMNS",
+	func(s string) ([]string, error) { return strings.Split(s, ","), nil },
+	nil,
+)
+
+var keyRenames IO[ns.KeyRenames] = envOpt(
+	"ENV_OUTPUT_RENAME",
+	ns.ParseKeyRenames,
+	nil,
+)
+
+var ErrRotateWithFiles error = errors.New(
+	"ENV_OUTPUT_ROTATE and ENV_OUTPUT_FILES are exclusive",
+)
+
+var ErrRotateBytesFormat error = errors.New(
+	"ENV_OUTPUT_ROTATE_BYTES is not supported by the output format",
+)
+
+var blockFormats []string = []string{
+	"arrow", "feather", "avro", "parquet", "ncdu", "sql", "table",
+}
+
+var outputRotation IO[ns.Rotation] = Bind(
+	All(
+		envOpt("ENV_OUTPUT_ROTATE_BYTES", ns.ParseByteSize, 0),
+		envOpt(
+			"ENV_OUTPUT_ROTATE_RECORDS",
+			func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) },
+			0,
+		),
+	),
+	func(limits []int64) IO[ns.Rotation] {
+		return Bind(
+			envOpt("ENV_OUTPUT_ROTATE", ns.ParseRotationPattern, ""),
+			Lift(func(pattern string) (ns.Rotation, error) {
+				return ns.Rotation{
+					Pattern:    pattern,
+					MaxBytes:   limits[0],
+					MaxRecords: limits[1],
+				}, nil
+			}),
+		)
+	},
+)
+
+var dryRun IO[bool] = envBool("ENV_DRY_RUN")
+
+var dryRunSampleEvery IO[int] = envOpt(
+	"ENV_DRY_RUN_SAMPLE_EVERY",
+	strconv.Atoi,
+	ns.DryRunSampleEveryDefault,
+)
+
+var tableBatchRows IO[int] = envOpt(
+	"ENV_TABLE_BATCH_ROWS",
+	strconv.Atoi,
+	ns.TableBatchRowsDefault,
+)
+
+var outputCompression IO[string] = envOpt(
+	"ENV_OUTPUT_COMPRESSION",
+	ns.ParseCompression,
+	ns.CompressionNone,
+)
+
+var outputFields IO[[]string] = envOpt(
+	"ENV_OUTPUT_FIELDS",
+	func(s string) ([]string, error) { return strings.Split(s, ","), nil },
+	nil,
+)
+
+var outputNoHeader IO[bool] = envBool("ENV_OUTPUT_NO_HEADER")
+
+var outputFiles IO[[]string] = envOpt(
+	"ENV_OUTPUT_FILES",
+	func(s string) ([]string, error) { return strings.Split(s, ","), nil },
+	nil,
+)
+
+var asn1Layout IO[ns.Asn1Layout] = envOpt(
+	"ENV_ASN1_LAYOUT",
+	ns.ParseAsn1Layout,
+	ns.Asn1LayoutCompat,
+)
+
+var outputTee IO[[]ns.TeeTarget] = envOpt(
+	"ENV_OUTPUT_TEE",
+	func(s string) ([]ns.TeeTarget, error) {
+		targets, e := ns.ParseTeeTargets(s)
+		for _, t := range targets {
+			if !slices.Contains(outputFormats, t.Format) {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, t.Format)
+			}
+		}
+		return targets, e
+	},
+	nil,
+)
+
+var outputFormat IO[string] = envOpt(
+	"ENV_OUTPUT_FORMAT",
+	func(s string) (string, error) {
+		if !slices.Contains(outputFormats, s) {
+			return "", fmt.Errorf("%w: %s", ErrUnknownFormat, s)
+		}
+		return s, nil
+	},
+	"",
+)
+
+var lockFile IO[string] = envOpt(
+	"ENV_LOCK_FILE",
+	func(s string) (string, error) { return s, nil },
+	"",
+)
+
+var lockWait IO[time.Duration] = envOpt(
+	"ENV_LOCK_WAIT",
+	time.ParseDuration,
+	0,
+)
+
+func withLock(ctx context.Context, f func() error) error {
+	name, e := lockFile(ctx)
+	if nil != e {
+		return exitcode.Wrap(exitcode.Config, e)
+	}
+	if "" == name {
+		return f()
+	}
+
+	wait, e := lockWait(ctx)
+	if nil != e {
+		return exitcode.Wrap(exitcode.Config, e)
+	}
+
+	lock, e := ns.AcquireFileLock(ctx, name, wait)
+	if errors.Is(e, ns.ErrLocked) {
+		return exitcode.Wrap(exitcode.Locked, e)
+	}
+	if nil != e {
+		return e
+	}
+
+	var fe error = f()
+	return errors.Join(fe, lock.Release())
+}
+
+var configured IO[options] = func(ctx context.Context) (options, error) {
+	o, e := opts(ctx)
+	return o, exitcode.Wrap(exitcode.Config, e)
+}
+
+var names2stats2jsonl2stdout IO[Void] = Bind(
+	configured,
+	func(o options) IO[Void] {
+		return func(ctx context.Context) (Void, error) {
+			return Empty, withLock(ctx, func() error {
+				return o.runOnRoot(ctx)
+			})
+		}
+	},
+)
+
+func (o options) schema() ns.Record {
+	fields, open := o.enrichers.DescribeFields()
+	for _, f := range o.annotations {
+		fields = append(fields, ns.FieldSchema{
+			Key:      f.Key,
+			Types:    []string{ns.JsonTypeOf(f.Value)},
+			Required: true,
+		})
+	}
+	if o.scannedAt {
+		fields = append(fields, ns.FieldSchema{
+			Key:      "scanned_at",
+			Types:    []string{"string"},
+			Format:   "date-time",
+			Required: true,
+		})
+	}
+	fields = slices.Concat(
+		ns.BasicFieldSchemas(ns.FileTypeToStringMapDefault),
+		fields,
+	)
+	if 0 < len(o.fields) {
+		fields = slices.DeleteFunc(fields, func(f ns.FieldSchema) bool {
+			return !slices.Contains(o.fields, f.Key)
+		})
+		open = false
+	}
+	return ns.RecordSchema{
+		Fields: fields,
+		Open:   open,
+	}.ToJsonSchemaRenamed(o.empty, o.keyCase, o.renames)
+}
+
+var schema2stdout IO[Void] = Bind(
+	configured,
+	Lift(func(o options) (Void, error) {
+		var enc *json.Encoder = json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return Empty, errors.Join(
+			enc.Encode(o.schema()),
+			o.enrichers.Close(),
+		)
+	}),
+)
+
+var emitSchema *bool = flag.Bool(
+	"emit-schema",
+	false,
+	"print the JSON Schema of the records instead of the records",
+)
+
+var emitAsn1Module *bool = flag.Bool(
+	"emit-asn1-module",
+	false,
+	"print the ASN.1 module of the DER encoded stats",
+)
+
+var asn1Module2stdout IO[Void] = func(_ context.Context) (Void, error) {
+	module, e := ns.BasicStatsAsn1Module("Names2Stats")
+	if nil != e {
+		return Empty, e
+	}
+	_, e = os.Stdout.WriteString(module)
+	return Empty, e
+}
+
+var quietMode IO[bool] = envBool("ENV_QUIET")
+
+type lineCounter struct{ lines int }
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	c.lines += bytes.Count(p, []byte("\n"))
+	return len(p), nil
+}
+
+func silence() (*lineCounter, error) {
+	devnull, e := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if nil != e {
+		return nil, e
+	}
+
+	var suppressed *lineCounter = &lineCounter{}
+	log.SetOutput(suppressed)
+	os.Stderr = devnull
+	return suppressed, nil
+}
+
+func summaryLine(e error, suppressed int) string {
+	return fmt.Sprintf(
+		"names2stats2jsonl: %s: %s (%d log lines suppressed)\n",
+		exitcode.Classify(e),
+		strings.ReplaceAll(e.Error(), "\n", "; "),
+		suppressed,
+	)
+}
+
+func main() {
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var run IO[Void] = names2stats2jsonl2stdout
+	switch {
+	case *emitSchema:
+		run = schema2stdout
+	case *emitAsn1Module:
+		run = asn1Module2stdout
+	}
+
+	var stderr *os.File = os.Stderr
+	var suppressed *lineCounter
+
+	quiet, e := quietMode(ctx)
+	if nil == e && quiet {
+		suppressed, e = silence()
+	}
+	if nil == e {
+		_, e = run(ctx)
+	} else {
+		e = exitcode.Wrap(exitcode.Config, e)
+	}
+
+	if nil != ctx.Err() {
+		e = exitcode.Wrap(exitcode.Signal, errors.Join(e, ctx.Err()))
+	}
+
+	if nil != e {
+		switch suppressed {
+		case nil:
+			log.Printf("%v\n", e)
+		default:
+			_, _ = stderr.WriteString(summaryLine(e, suppressed.lines))
+		}
+	}
+
+	cancel()
+	exitcode.Exit(e)
 }
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+
+	ns "github.com/takanoriyanagitani/go-names2stats"
+	"github.com/takanoriyanagitani/go-names2stats/exitcode"
+	. "github.com/takanoriyanagitani/go-names2stats/util"
+)
+
+var envValByKey func(string) IO[string] = Lift(
+	func(key string) (string, error) {
+		val, found := os.LookupEnv(key)
+		switch found {
+		case true:
+			return val, nil
+		default:
+			return "", fmt.Errorf("env var %s missing", key)
+		}
+	},
+)
+
+var maxDepth IO[int] = func(ctx context.Context) (int, error) {
+	_, found := os.LookupEnv("ENV_MAX_DEPTH")
+	if !found {
+		return 0, nil
+	}
+	return Bind(envValByKey("ENV_MAX_DEPTH"), Lift(strconv.Atoi))(ctx)
+}
+
+var pathCase IO[ns.PathCase] = func(ctx context.Context) (ns.PathCase, error) {
+	_, found := os.LookupEnv("ENV_CASE_INSENSITIVE")
+	if !found {
+		return ns.PathCaseSensitive, nil
+	}
+	return Bind(
+		envValByKey("ENV_CASE_INSENSITIVE"),
+		Lift(func(s string) (ns.PathCase, error) {
+			insensitive, e := strconv.ParseBool(s)
+			switch insensitive {
+			case true:
+				return ns.PathCaseInsensitive, e
+			default:
+				return ns.PathCaseSensitive, e
+			}
+		}),
+	)(ctx)
+}
+
+func readSnapshot(name string) (ns.Snapshot, error) {
+	var empty ns.Snapshot
+
+	f, e := os.Open(name)
+	if nil != e {
+		return empty, e
+	}
+	defer f.Close()
+
+	fi, e := f.Stat()
+	if nil != e {
+		return empty, e
+	}
+
+	rdr, e := ns.ReaderToDecompressed(f)
+	if nil != e {
+		return empty, e
+	}
+	defer rdr.Close()
+
+	snap, e := ns.ReadSnapshot(rdr, fi.ModTime())
+	if nil != e {
+		return empty, fmt.Errorf("%s: %w", name, e)
+	}
+	return snap, nil
+}
+
+func snapshot(key string) IO[ns.Snapshot] {
+	return Bind(envValByKey(key), Lift(readSnapshot))
+}
+
+var diff IO[*ns.SnapshotDiff] = Bind(
+	All(snapshot("ENV_SNAPSHOT_OLD"), snapshot("ENV_SNAPSHOT_NEW")),
+	func(snaps []ns.Snapshot) IO[*ns.SnapshotDiff] {
+		return Bind(
+			pathCase,
+			Lift(func(c ns.PathCase) (*ns.SnapshotDiff, error) {
+				return ns.NewSnapshotDiffCase(snaps[0], snaps[1], c)
+			}),
+		)
+	},
+)
+
+var growth2stdout IO[Void] = Bind(
+	diff,
+	func(d *ns.SnapshotDiff) IO[Void] {
+		return Bind(
+			maxDepth,
+			Lift(func(depth int) (Void, error) {
+				return Empty, ns.RecordsToWriter(os.Stdout)(d.Records(depth))
+			}),
+		)
+	},
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	_, e := growth2stdout(ctx)
+	if nil != e {
+		log.Printf("%v\n", e)
+	}
+
+	cancel()
+	exitcode.Exit(e)
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+
+	ns "github.com/takanoriyanagitani/go-names2stats"
+	"github.com/takanoriyanagitani/go-names2stats/exitcode"
+	. "github.com/takanoriyanagitani/go-names2stats/util"
+)
+
+var envValByKey func(string) IO[string] = Lift(
+	func(key string) (string, error) {
+		val, found := os.LookupEnv(key)
+		switch found {
+		case true:
+			return val, nil
+		default:
+			return "", fmt.Errorf("env var %s missing", key)
+		}
+	},
+)
+
+func envOpt[T any](key string, parse func(string) (T, error), alt T) IO[T] {
+	return func(ctx context.Context) (T, error) {
+		_, found := os.LookupEnv(key)
+		switch found {
+		case true:
+			return Bind(envValByKey(key), Lift(parse))(ctx)
+		default:
+			return alt, nil
+		}
+	}
+}
+
+var snapshotName IO[string] = envOpt(
+	"ENV_SNAPSHOT",
+	func(s string) (string, error) { return s, nil },
+	"",
+)
+
+var rootDirname IO[string] = envValByKey("ENV_ROOT_DIR_NAME")
+
+func snapshotToTree(name string) (*ns.DirNode, error) {
+	f, e := os.Open(name)
+	if nil != e {
+		return nil, e
+	}
+	defer f.Close()
+
+	rdr, e := ns.ReaderToDecompressed(f)
+	if nil != e {
+		return nil, e
+	}
+	defer rdr.Close()
+
+	var stats iter.Seq2[ns.BasicStat, error] = ns.StringToFileTypeDefault.
+		ReaderToBasicStats(rdr)
+	return ns.BasicStatsToDirTree(stats)
+}
+
+type treeView struct {
+	mu      sync.Mutex
+	root    *ns.DirNode
+	live    bool
+	done    bool
+	errors  int64
+	walkErr error
+}
+
+func (t *treeView) insert(s ns.BasicStat) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.root.Insert(s)
+	return nil
+}
+
+func (t *treeView) skip(_ string, _ error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errors++
+	return nil
+}
+
+func (t *treeView) walk(ctx context.Context, d ns.RootDirname) {
+	e := d.WithRoot(func(rt ns.Root) error {
+		return ns.Walker{OnStat: t.insert, OnError: t.skip}.Walk(ctx, rt, ".")
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.done, t.walkErr = true, e
+}
+
+func (t *treeView) status() string {
+	switch {
+	case !t.live:
+		return ""
+	case nil != t.walkErr:
+		return fmt.Sprintf("walk failed: %v", t.walkErr)
+	case t.done:
+		return fmt.Sprintf("walk done(%d errors skipped)", t.errors)
+	default:
+		return fmt.Sprintf(
+			"walking... %d entries, %d errors skipped",
+			t.root.Entries,
+			t.errors,
+		)
+	}
+}
+
+var snapshotView IO[*treeView] = func(ctx context.Context) (*treeView, error) {
+	name, e := snapshotName(ctx)
+	if nil != e || "" == name {
+		return nil, e
+	}
+	root, e := snapshotToTree(name)
+	return &treeView{root: root}, e
+}
+
+var liveView IO[*treeView] = Bind(
+	rootDirname,
+	func(d string) IO[*treeView] {
+		return func(ctx context.Context) (*treeView, error) {
+			var t *treeView = &treeView{root: ns.NewDirTree(), live: true}
+			go t.walk(ctx, ns.RootDirname(d))
+			return t, nil
+		}
+	},
+)
+
+var view IO[*treeView] = Bind(
+	snapshotView,
+	func(t *treeView) IO[*treeView] {
+		if nil != t {
+			return Of(t)
+		}
+		return liveView
+	},
+)
+
+const barWidth int = 20
+
+const help string = `commands:
+  <n>           enter the n-th entry
+  .. | u        go up
+  e <n|.> FILE  export the stats under the entry(or here) as JSONL
+  q             quit
+`
+
+type browser struct {
+	view    *treeView
+	cwd     *ns.DirNode
+	listing []*ns.DirNode
+	notes   bytes.Buffer
+	out     *bufio.Writer
+	human   ns.HumanRenderer
+}
+
+func (b *browser) bar(size int64) string {
+	var filled int
+	if 0 < b.cwd.Size {
+		filled = int(size * int64(barWidth) / b.cwd.Size)
+	}
+	return strings.Repeat("#", filled) + strings.Repeat(" ", barWidth-filled)
+}
+
+func (b *browser) show() error {
+	b.view.mu.Lock()
+	defer b.view.mu.Unlock()
+
+	b.listing = b.cwd.BySize()
+
+	var w *bufio.Writer = b.out
+	if b.human.Color {
+		_, _ = w.WriteString("\x1b[H\x1b[2J")
+	}
+	var status string = b.view.status()
+	switch {
+	case "" == status:
+	case b.view.done:
+		_, _ = fmt.Fprintln(w, status)
+	default:
+		_, _ = fmt.Fprintf(w, "%s(Enter to refresh)\n", status)
+	}
+	_, _ = fmt.Fprintf(
+		w,
+		"--- %s (%s, %d entries)\n",
+		b.cwd.Path(),
+		ns.HumanSize(b.cwd.Size),
+		b.cwd.Entries,
+	)
+	for i, child := range b.listing {
+		var name string = child.Name
+		if child.IsDir() {
+			name += "/"
+		}
+		_, _ = fmt.Fprintf(
+			w,
+			"%4d) %7s [%s] %s\n",
+			i+1,
+			ns.HumanSize(child.Size),
+			b.bar(child.Size),
+			name,
+		)
+	}
+	_, _ = b.notes.WriteTo(w)
+	_, _ = w.WriteString("> ")
+	return w.Flush()
+}
+
+func (b *browser) pick(arg string) (*ns.DirNode, error) {
+	if "." == arg {
+		return b.cwd, nil
+	}
+
+	i, e := strconv.Atoi(arg)
+	if nil != e {
+		return nil, e
+	}
+	if i < 1 || len(b.listing) < i {
+		return nil, fmt.Errorf("no such entry: %d", i)
+	}
+	return b.listing[i-1], nil
+}
+
+func export(node *ns.DirNode, name string) error {
+	f, e := os.Create(name)
+	if nil != e {
+		return e
+	}
+
+	var seq iter.Seq2[ns.BasicStat, error] = iter.Seq2[ns.BasicStat, error](
+		node.Stats(),
+	)
+	e = ns.FileTypeToStringDefault.EnrichedStatsToWriter(f)(
+		ns.BasicStatsToEnriched(seq),
+	)
+	return errors.Join(e, f.Close())
+}
+
+func (b *browser) exec(line string) (quit bool, e error) {
+	var args []string = strings.Fields(line)
+	if 0 == len(args) {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "q":
+		return true, nil
+	case "..", "u":
+		if nil != b.cwd.Parent {
+			b.cwd = b.cwd.Parent
+		}
+		return false, nil
+	case "?", "h":
+		_, e = b.notes.WriteString(help)
+		return false, e
+	case "e":
+		if 3 != len(args) {
+			return false, errors.New("usage: e <n|.> FILE")
+		}
+		node, e := b.pick(args[1])
+		if nil != e {
+			return false, e
+		}
+		b.view.mu.Lock()
+		defer b.view.mu.Unlock()
+		return false, export(node, args[2])
+	}
+
+	node, e := b.pick(args[0])
+	if nil != e {
+		return false, e
+	}
+	if !node.IsDir() {
+		return false, fmt.Errorf("not a directory: %s", node.Path())
+	}
+	b.cwd = node
+	return false, nil
+}
+
+func (b *browser) loop(ctx context.Context, rdr io.Reader) error {
+	var s *bufio.Scanner = bufio.NewScanner(rdr)
+	for {
+		e := b.show()
+		if nil != e {
+			return e
+		}
+
+		if nil != ctx.Err() || !s.Scan() {
+			return errors.Join(ctx.Err(), s.Err())
+		}
+
+		quit, e := b.exec(s.Text())
+		if nil != e {
+			_, _ = fmt.Fprintf(&b.notes, "error: %v\n", e)
+		}
+		if quit {
+			return b.out.Flush()
+		}
+	}
+}
+
+var browse IO[Void] = Bind(
+	view,
+	func(t *treeView) IO[Void] {
+		return func(ctx context.Context) (Void, error) {
+			var b browser = browser{
+				view: t,
+				cwd:  t.root,
+				out:  bufio.NewWriter(os.Stdout),
+				human: ns.HumanRenderer{
+					Color: ns.IsTerminal(os.Stdout) && ns.ColorAllowed(),
+				},
+			}
+			if !ns.IsTerminal(os.Stdin) || !ns.IsTerminal(os.Stdout) {
+				return Empty, b.loop(ctx, os.Stdin)
+			}
+
+			e := b.tui(ctx, os.Stdin)
+			if errors.Is(e, ErrRawUnsupported) {
+				return Empty, b.loop(ctx, os.Stdin)
+			}
+			return Empty, e
+		}
+	},
+)
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	_, e := browse(ctx)
+	if nil != e {
+		log.Printf("%v\n", e)
+	}
+
+	cancel()
+	exitcode.Exit(e)
+}
@@ -0,0 +1,12 @@
+//go:build linux
+
+package main
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+const (
+	ioctlGetTermios uint = unix.TCGETS
+	ioctlSetTermios uint = unix.TCSETS
+)
@@ -0,0 +1,15 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+	"os"
+)
+
+func rawMode(_ int) (restore func() error, e error) {
+	return nil, ErrRawUnsupported
+}
+
+func termSize(_ int) (rows int, cols int) { return 24, 80 }
+
+func resized() <-chan os.Signal { return nil }
@@ -0,0 +1,48 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+func rawMode(fd int) (restore func() error, e error) {
+	saved, e := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if nil != e {
+		return nil, e
+	}
+
+	var raw unix.Termios = *saved
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP |
+		unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	e = unix.IoctlSetTermios(fd, ioctlSetTermios, &raw)
+	if nil != e {
+		return nil, e
+	}
+	return func() error {
+		return unix.IoctlSetTermios(fd, ioctlSetTermios, saved)
+	}, nil
+}
+
+func termSize(fd int) (rows int, cols int) {
+	ws, e := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
+	if nil != e || 0 == ws.Row || 0 == ws.Col {
+		return 24, 80
+	}
+	return int(ws.Row), int(ws.Col)
+}
+
+func resized() <-chan os.Signal {
+	var ch chan os.Signal = make(chan os.Signal, 1)
+	signal.Notify(ch, unix.SIGWINCH)
+	return ch
+}
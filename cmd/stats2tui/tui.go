@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	ns "github.com/takanoriyanagitani/go-names2stats"
+)
+
+var ErrRawUnsupported error = errors.New("raw terminal mode unsupported")
+
+const refreshInterval time.Duration = 500 * time.Millisecond
+
+const keyHints string = "up/k down/j move  Enter open  Backspace up  e export(E: here)  q quit"
+
+type key int
+
+const (
+	keyRune key = iota
+	keyUp
+	keyDown
+	keyEnter
+	keyBack
+	keyEscape
+	keyQuit
+)
+
+type keypress struct {
+	key  key
+	char rune
+}
+
+func decodeKeys(buf []byte) []keypress {
+	var ret []keypress
+	for 0 < len(buf) {
+		switch {
+		case bytes.HasPrefix(buf, []byte("\x1b[A")), bytes.HasPrefix(buf, []byte("\x1bOA")):
+			ret, buf = append(ret, keypress{key: keyUp}), buf[3:]
+		case bytes.HasPrefix(buf, []byte("\x1b[B")), bytes.HasPrefix(buf, []byte("\x1bOB")):
+			ret, buf = append(ret, keypress{key: keyDown}), buf[3:]
+		case bytes.HasPrefix(buf, []byte("\x1b[C")), bytes.HasPrefix(buf, []byte("\x1bOC")):
+			ret, buf = append(ret, keypress{key: keyEnter}), buf[3:]
+		case bytes.HasPrefix(buf, []byte("\x1b[D")), bytes.HasPrefix(buf, []byte("\x1bOD")):
+			ret, buf = append(ret, keypress{key: keyBack}), buf[3:]
+		case bytes.HasPrefix(buf, []byte("\x1b[")):
+			// an unknown CSI sequence: drop it up to its final byte
+			var end int = bytes.IndexFunc(buf[2:], func(r rune) bool {
+				return 0x40 <= r && r <= 0x7e
+			})
+			switch end {
+			case -1:
+				buf = nil
+			default:
+				buf = buf[2+end+1:]
+			}
+		default:
+			r, size := utf8.DecodeRune(buf)
+			buf = buf[size:]
+			switch r {
+			case '\x1b':
+				ret = append(ret, keypress{key: keyEscape})
+			case '\r', '\n':
+				ret = append(ret, keypress{key: keyEnter})
+			case '\x7f', '\b':
+				ret = append(ret, keypress{key: keyBack})
+			case '\x03', '\x04':
+				ret = append(ret, keypress{key: keyQuit})
+			default:
+				ret = append(ret, keypress{key: keyRune, char: r})
+			}
+		}
+	}
+	return ret
+}
+
+func readKeys(rdr io.Reader) <-chan keypress {
+	var keys chan keypress = make(chan keypress)
+	go func() {
+		defer close(keys)
+
+		var buf [64]byte
+		for {
+			n, e := rdr.Read(buf[:])
+			for _, k := range decodeKeys(buf[:n]) {
+				keys <- k
+			}
+			if nil != e {
+				return
+			}
+		}
+	}()
+	return keys
+}
+
+type screen struct {
+	*browser
+	fd     int
+	sel    int
+	top    int
+	export *ns.DirNode
+	input  []rune
+}
+
+func (s *screen) clamp(body int) {
+	s.sel = max(0, min(s.sel, len(s.listing)-1))
+	switch {
+	case s.sel < s.top:
+		s.top = s.sel
+	case s.top+body <= s.sel:
+		s.top = s.sel - body + 1
+	}
+	s.top = max(0, min(s.top, len(s.listing)-body))
+}
+
+func (s *screen) line(w *strings.Builder, cols int, text string) {
+	if cols < utf8.RuneCountInString(text) {
+		text = string([]rune(text)[:cols])
+	}
+	_, _ = w.WriteString(text)
+	_, _ = w.WriteString("\x1b[K\n")
+}
+
+func (s *screen) draw() error {
+	rows, cols := termSize(s.fd)
+
+	s.view.mu.Lock()
+	s.listing = s.cwd.BySize()
+	var status string = s.view.status()
+	var w strings.Builder
+	_, _ = w.WriteString("\x1b[H")
+
+	var body int = rows - 3
+	if "" != status {
+		s.line(&w, cols, status)
+		body--
+	}
+	s.line(&w, cols, fmt.Sprintf(
+		"--- %s (%s, %d entries)",
+		s.cwd.Path(),
+		ns.HumanSize(s.cwd.Size),
+		s.cwd.Entries,
+	))
+
+	body = max(1, body)
+	s.clamp(body)
+	for i := s.top; i < min(len(s.listing), s.top+body); i++ {
+		var child *ns.DirNode = s.listing[i]
+		var name string = child.Name
+		if child.IsDir() {
+			name += "/"
+		}
+		var text string = fmt.Sprintf(
+			" %7s [%s] %s",
+			ns.HumanSize(child.Size),
+			s.bar(child.Size),
+			name,
+		)
+		if i == s.sel {
+			_, _ = w.WriteString("\x1b[7m")
+			s.line(&w, cols, text)
+			_, _ = w.WriteString("\x1b[0m")
+			continue
+		}
+		s.line(&w, cols, text)
+	}
+	s.view.mu.Unlock()
+
+	_, _ = w.WriteString("\x1b[J")
+	_, _ = w.WriteString(fmt.Sprintf("\x1b[%d;1H", rows-1))
+
+	var notes []string = strings.Split(strings.TrimSpace(s.notes.String()), "\n")
+	s.line(&w, cols, notes[len(notes)-1])
+	switch s.export {
+	case nil:
+		_, _ = w.WriteString(keyHints)
+	default:
+		_, _ = w.WriteString(fmt.Sprintf(
+			"export %s to(Enter to write, Esc to cancel): %s",
+			s.export.Path(),
+			string(s.input),
+		))
+	}
+	_, _ = w.WriteString("\x1b[K")
+
+	_, _ = s.out.WriteString(w.String())
+	return s.out.Flush()
+}
+
+func (s *screen) selected() *ns.DirNode {
+	if s.sel < 0 || len(s.listing) <= s.sel {
+		return nil
+	}
+	return s.listing[s.sel]
+}
+
+func (s *screen) prompt(k keypress) {
+	switch k.key {
+	case keyEscape, keyQuit:
+		s.export, s.input = nil, nil
+	case keyBack:
+		if 0 < len(s.input) {
+			s.input = s.input[:len(s.input)-1]
+		}
+	case keyEnter:
+		var node *ns.DirNode = s.export
+		var name string = strings.TrimSpace(string(s.input))
+		s.export, s.input = nil, nil
+		if "" == name {
+			return
+		}
+
+		s.view.mu.Lock()
+		e := export(node, name)
+		s.view.mu.Unlock()
+		switch e {
+		case nil:
+			_, _ = fmt.Fprintf(&s.notes, "exported %s to %s\n", node.Path(), name)
+		default:
+			_, _ = fmt.Fprintf(&s.notes, "error: %v\n", e)
+		}
+	case keyRune:
+		s.input = append(s.input, k.char)
+	}
+}
+
+func (s *screen) press(k keypress) (quit bool) {
+	s.notes.Reset()
+	if nil != s.export {
+		s.prompt(k)
+		return false
+	}
+
+	switch {
+	case keyQuit == k.key, keyRune == k.key && 'q' == k.char:
+		return true
+	case keyUp == k.key, keyRune == k.key && 'k' == k.char:
+		s.sel--
+	case keyDown == k.key, keyRune == k.key && 'j' == k.char:
+		s.sel++
+	case keyBack == k.key, keyRune == k.key && ('h' == k.char || 'u' == k.char):
+		if nil == s.cwd.Parent {
+			return false
+		}
+		var prev *ns.DirNode = s.cwd
+		s.cwd = s.cwd.Parent
+		s.sel, s.top = 0, 0
+		for i, child := range s.cwd.BySize() {
+			if prev == child {
+				s.sel = i
+			}
+		}
+	case keyEnter == k.key, keyRune == k.key && 'l' == k.char:
+		var node *ns.DirNode = s.selected()
+		switch {
+		case nil == node:
+		case !node.IsDir():
+			_, _ = fmt.Fprintf(&s.notes, "not a directory: %s\n", node.Path())
+		default:
+			s.cwd, s.sel, s.top = node, 0, 0
+		}
+	case keyRune == k.key && 'e' == k.char:
+		s.export = s.selected()
+		if nil == s.export {
+			s.export = s.cwd
+		}
+	case keyRune == k.key && 'E' == k.char:
+		s.export = s.cwd
+	}
+	return false
+}
+
+func (s *screen) loop(ctx context.Context, keys <-chan keypress) error {
+	var refresh <-chan time.Time
+	if s.view.live {
+		var tick *time.Ticker = time.NewTicker(refreshInterval)
+		defer tick.Stop()
+		refresh = tick.C
+	}
+	var winch <-chan os.Signal = resized()
+
+	for {
+		e := s.draw()
+		if nil != e {
+			return e
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-refresh:
+		case <-winch:
+		case k, ok := <-keys:
+			if !ok || s.press(k) {
+				return nil
+			}
+		}
+	}
+}
+
+func (b *browser) tui(ctx context.Context, in *os.File) error {
+	restore, e := rawMode(int(in.Fd()))
+	if nil != e {
+		return e
+	}
+
+	_, _ = b.out.WriteString("\x1b[?1049h\x1b[?25l")
+	var s screen = screen{browser: b, fd: int(os.Stdout.Fd())}
+	e = s.loop(ctx, readKeys(in))
+
+	_, _ = b.out.WriteString("\x1b[?25h\x1b[?1049l")
+	return errors.Join(e, b.out.Flush(), restore())
+}
@@ -0,0 +1,46 @@
+package names2stats
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	CompressionNone string = "none"
+	CompressionGzip string = "gzip"
+	CompressionZstd string = "zstd"
+)
+
+var ErrUnknownCompression error = errors.New("unknown compression")
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func ParseCompression(s string) (string, error) {
+	switch s {
+	case "", CompressionNone:
+		return CompressionNone, nil
+	case CompressionGzip, CompressionZstd:
+		return s, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownCompression, s)
+	}
+}
+
+func CompressWriter(wtr io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case "", CompressionNone:
+		return nopWriteCloser{wtr}, nil
+	case CompressionGzip:
+		return gzip.NewWriter(wtr), nil
+	case CompressionZstd:
+		return zstd.NewWriter(wtr)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCompression, compression)
+	}
+}
@@ -0,0 +1,102 @@
+package names2stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+func CsvValue(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+
+	raw, e := json.Marshal(val)
+	if nil != e {
+		return fmt.Sprint(val)
+	}
+	return string(raw)
+}
+
+func IsStatRecord(rec Record) bool {
+	typ, found := rec.Get("record_type")
+	return !found || RecordTypeStat == typ
+}
+
+type CsvOptions struct {
+	Columns  []string
+	NoHeader bool
+	Comma    rune
+	CRLF     bool
+}
+
+func (o CsvOptions) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	return func(records RecordIter) error {
+		var cw *csv.Writer = csv.NewWriter(wtr)
+		cw.UseCRLF = o.CRLF
+		if 0 != o.Comma {
+			cw.Comma = o.Comma
+		}
+
+		var columns []string = o.Columns
+		var row []string
+		for rec, e := range records {
+			if nil != e {
+				cw.Flush()
+				return errors.Join(e, cw.Error())
+			}
+
+			if !IsStatRecord(rec) {
+				continue
+			}
+
+			if nil == columns {
+				columns = make([]string, 0, len(rec))
+				for _, f := range rec {
+					columns = append(columns, f.Key)
+				}
+			}
+
+			if nil == row && !o.NoHeader {
+				e = cw.Write(columns)
+				if nil != e {
+					return e
+				}
+			}
+
+			row = make([]string, 0, len(columns))
+			for _, col := range columns {
+				val, _ := rec.Get(col)
+				row = append(row, CsvValue(val))
+			}
+
+			e = cw.Write(row)
+			if nil != e {
+				return e
+			}
+		}
+
+		if nil == row && nil != columns && !o.NoHeader {
+			e := cw.Write(columns)
+			if nil != e {
+				return e
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+	}
+}
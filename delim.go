@@ -0,0 +1,60 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var ErrInvalidDelim error = errors.New("invalid name delimiter")
+
+type NameDelim byte
+
+const (
+	NameDelimNewline NameDelim = '\n'
+	NameDelimNul     NameDelim = 0
+)
+
+func ParseNameDelim(s string) (NameDelim, error) {
+	switch s {
+	case "newline", "lf":
+		return NameDelimNewline, nil
+	case "nul", "null", "0":
+		return NameDelimNul, nil
+	default:
+		return NameDelimNewline, fmt.Errorf("%w: %q: want newline or nul", ErrInvalidDelim, s)
+	}
+}
+
+func (d NameDelim) split(data []byte, atEOF bool) (int, []byte, error) {
+	i := bytes.IndexByte(data, byte(d))
+	switch {
+	case 0 <= i:
+		return i + 1, data[:i], nil
+	case atEOF && 0 < len(data):
+		return len(data), data, nil
+	default:
+		return 0, nil, nil
+	}
+}
+
+func (d NameDelim) ReaderToNameIter(rdr io.Reader) NameIter {
+	return func(yield func(string, error) bool) {
+		var s *bufio.Scanner = bufio.NewScanner(rdr)
+		if NameDelimNewline != d {
+			s.Split(d.split)
+		}
+		for s.Scan() {
+			if !yield(s.Text(), nil) {
+				return
+			}
+		}
+
+		e := s.Err()
+		if nil != e {
+			yield("", e)
+		}
+	}
+}
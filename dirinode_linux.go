@@ -0,0 +1,128 @@
+//go:build linux
+
+package names2stats
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"syscall"
+)
+
+const (
+	dirent64TypeOffset int = 18
+	dirent64NameOffset int = 19
+)
+
+type inodeDirEntry struct {
+	root  *os.Root
+	dir   string
+	name  string
+	typ   fs.FileMode
+	inode uint64
+}
+
+func (d inodeDirEntry) Name() string               { return d.name }
+func (d inodeDirEntry) IsDir() bool                { return d.typ.IsDir() }
+func (d inodeDirEntry) Type() fs.FileMode          { return d.typ }
+func (d inodeDirEntry) Inode() uint64              { return d.inode }
+func (d inodeDirEntry) String() string             { return fs.FormatDirEntry(d) }
+func (d inodeDirEntry) Info() (fs.FileInfo, error) { return d.root.Lstat(path.Join(d.dir, d.name)) }
+
+func direntType(t byte) (fs.FileMode, bool) {
+	switch t {
+	case syscall.DT_REG:
+		return 0, true
+	case syscall.DT_DIR:
+		return fs.ModeDir, true
+	case syscall.DT_LNK:
+		return fs.ModeSymlink, true
+	case syscall.DT_FIFO:
+		return fs.ModeNamedPipe, true
+	case syscall.DT_SOCK:
+		return fs.ModeSocket, true
+	case syscall.DT_CHR:
+		return fs.ModeDevice | fs.ModeCharDevice, true
+	case syscall.DT_BLK:
+		return fs.ModeDevice, true
+	default:
+		return 0, false
+	}
+}
+
+func (d inodeDirEntry) parseDirent64(buf []byte, entries []fs.DirEntry) ([]fs.DirEntry, error) {
+	for dirent64NameOffset <= len(buf) {
+		var reclen int = int(binary.NativeEndian.Uint16(buf[16:18]))
+		if reclen < dirent64NameOffset || len(buf) < reclen {
+			return entries, nil
+		}
+
+		var name []byte = buf[dirent64NameOffset:reclen]
+		name, _, _ = bytes.Cut(name, []byte{0})
+		switch string(name) {
+		case ".", "..":
+		default:
+			var ent inodeDirEntry = d
+			ent.name = string(name)
+			ent.inode = binary.NativeEndian.Uint64(buf[0:8])
+
+			typ, known := direntType(buf[dirent64TypeOffset])
+			if !known {
+				info, e := ent.Info()
+				if nil != e {
+					return entries, e
+				}
+				typ = info.Mode().Type()
+			}
+			ent.typ = typ
+			entries = append(entries, ent)
+		}
+		buf = buf[reclen:]
+	}
+	return entries, nil
+}
+
+func readDirInodes(r *os.Root, dir string) ([]fs.DirEntry, error) {
+	f, e := r.Open(dir)
+	if nil != e {
+		return nil, e
+	}
+	defer f.Close()
+
+	raw, e := f.SyscallConn()
+	if nil != e {
+		return nil, e
+	}
+
+	var tmpl inodeDirEntry = inodeDirEntry{root: r, dir: dir}
+	var entries []fs.DirEntry
+	var buf []byte = make([]byte, 32*1024)
+	for {
+		var n int
+		var re error
+		e = raw.Read(func(fd uintptr) bool {
+			n, re = syscall.ReadDirent(int(fd), buf)
+			return true
+		})
+		switch {
+		case nil != e:
+			return nil, e
+		case nil != re:
+			return nil, re
+		case 0 == n:
+			slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+				return strings.Compare(a.Name(), b.Name())
+			})
+			return entries, nil
+		}
+
+		entries, e = tmpl.parseDirent64(buf[:n], entries)
+		if nil != e {
+			return nil, e
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package names2stats
+
+import (
+	"io/fs"
+	"os"
+)
+
+func readDirInodes(r *os.Root, dir string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(r.FS(), dir)
+}
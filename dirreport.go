@@ -0,0 +1,67 @@
+package names2stats
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+const RecordTypeDirTotal string = "dir_total"
+
+const TopDirsDefault int = 10
+
+type LargestDirsReport struct {
+	Top  int
+	Tree *DirNode
+}
+
+func NewLargestDirsReport(top int) *LargestDirsReport {
+	return &LargestDirsReport{Top: top, Tree: NewDirTree()}
+}
+
+func (rep *LargestDirsReport) Filter(
+	_ Root,
+	stats iter.Seq2[BasicStat, error],
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for s, e := range stats {
+			if nil != e {
+				yield(s, e)
+				return
+			}
+			rep.Tree.Insert(s)
+		}
+	}
+}
+
+func (rep *LargestDirsReport) Largest() []*DirNode {
+	var dirs []*DirNode
+	for node := range rep.Tree.All() {
+		if nil != node.Parent && node.IsDir() {
+			dirs = append(dirs, node)
+		}
+	}
+
+	slices.SortFunc(dirs, func(a, b *DirNode) int {
+		return cmp.Or(
+			cmp.Compare(b.Size, a.Size),
+			cmp.Compare(a.Path(), b.Path()),
+		)
+	})
+	return dirs[:min(rep.Top, len(dirs))]
+}
+
+func (rep *LargestDirsReport) Summary() []Record {
+	var dirs []*DirNode = rep.Largest()
+	var ret []Record = make([]Record, 0, len(dirs))
+	for i, d := range dirs {
+		ret = append(ret, Record{
+			{Key: "record_type", Value: RecordTypeDirTotal},
+			{Key: "rank", Value: int64(i + 1)},
+			{Key: "dir", Value: d.Path()},
+			{Key: "bytes", Value: d.Size},
+			{Key: "entries", Value: d.Entries},
+		})
+	}
+	return ret
+}
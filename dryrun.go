@@ -0,0 +1,85 @@
+package names2stats
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	RecordTypeDryRun         string = "dry_run"
+	DryRunSampleEveryDefault int    = 100
+)
+
+type DryRun struct {
+	SampleEvery int
+	Stat        FilenameToBasicStat
+	Enrich      func(BasicStat) error
+}
+
+type DryRunEstimate struct {
+	Names        int64
+	Sampled      int64
+	Errors       int64
+	SampledBytes int64
+	SampledTime  time.Duration
+}
+
+func (d DryRun) Estimate(ctx context.Context, names TaggedNameIter) (DryRunEstimate, error) {
+	var every int64 = int64(d.SampleEvery)
+	if every <= 0 {
+		every = int64(DryRunSampleEveryDefault)
+	}
+
+	var est DryRunEstimate
+	for tagged, e := range names {
+		if nil == e {
+			e = ctx.Err()
+		}
+		if nil != e {
+			return est, e
+		}
+
+		est.Names++
+		if 0 != (est.Names-1)%every {
+			continue
+		}
+
+		var started time.Time = time.Now()
+		s, e := d.Stat(tagged.Name)
+		if nil == e && nil != d.Enrich {
+			e = d.Enrich(s)
+		}
+		est.SampledTime += time.Since(started)
+		est.Sampled++
+
+		switch {
+		case errors.Is(e, ErrAlreadySeen), errors.Is(e, ErrNotOwned):
+		case nil != e:
+			est.Errors++
+		case FileTypeRglr == s.FileType:
+			est.SampledBytes += s.Size
+		}
+	}
+	return est, nil
+}
+
+func (e DryRunEstimate) scale(v float64) float64 {
+	if 0 == e.Sampled {
+		return 0
+	}
+	return v * float64(e.Names) / float64(e.Sampled)
+}
+
+func (e DryRunEstimate) ToRecord() Record {
+	return Record{
+		{Key: "record_type", Value: RecordTypeDryRun},
+		{Key: "names", Value: e.Names},
+		{Key: "sampled", Value: e.Sampled},
+		{Key: "sample_errors", Value: e.Errors},
+		{Key: "stat_calls", Value: e.Names},
+		{Key: "est_regular_bytes", Value: int64(e.scale(float64(e.SampledBytes)))},
+		{Key: "est_errors", Value: int64(e.scale(float64(e.Errors)))},
+		{Key: "est_wall_seconds", Value: e.scale(e.SampledTime.Seconds())},
+	}
+}
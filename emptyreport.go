@@ -0,0 +1,79 @@
+package names2stats
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+const RecordTypeEmptySummary string = "empty_summary"
+
+func (r Root) IsEmptyDir(name string) (bool, error) {
+	f, e := r.Root.Open(name)
+	if nil != e {
+		return false, e
+	}
+	defer f.Close()
+
+	_, e = f.ReadDir(1)
+	switch {
+	case errors.Is(e, io.EOF):
+		return true, nil
+	case nil == e:
+		return false, nil
+	default:
+		return false, e
+	}
+}
+
+type EmptyReport struct {
+	ZeroByteFiles int64
+	EmptyDirs     int64
+}
+
+func (rep *EmptyReport) Filter(
+	r Root,
+	stats iter.Seq2[BasicStat, error],
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for s, e := range stats {
+			if nil != e {
+				yield(s, e)
+				return
+			}
+
+			switch s.FileType {
+			case FileTypeRglr:
+				if 0 != s.Size {
+					continue
+				}
+				rep.ZeroByteFiles++
+			case FileTypeFldr:
+				empty, e := r.IsEmptyDir(s.Path)
+				if nil != e {
+					yield(s, e)
+					return
+				}
+				if !empty {
+					continue
+				}
+				rep.EmptyDirs++
+			default:
+				continue
+			}
+
+			if !yield(s, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (rep *EmptyReport) Summary() []Record {
+	return []Record{{
+		{Key: "record_type", Value: RecordTypeEmptySummary},
+		{Key: "zero_byte_files", Value: rep.ZeroByteFiles},
+		{Key: "empty_dirs", Value: rep.EmptyDirs},
+		{Key: "total", Value: rep.ZeroByteFiles + rep.EmptyDirs},
+	}}
+}
@@ -0,0 +1,259 @@
+package names2stats
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"iter"
+	"maps"
+	"mime"
+	"os"
+	"path"
+	"slices"
+	"sync"
+)
+
+var (
+	ErrUnknownEnricher error = errors.New("unknown enricher")
+	ErrReservedKey     error = errors.New("reserved key")
+	ErrDuplicateKey    error = errors.New("duplicate key")
+)
+
+type EnrichedStat struct {
+	BasicStat
+	Extra map[string]any
+}
+
+func (e EnrichedStat) ToRecord(t2s FileTypeToString) Record {
+	var rec Record = e.BasicStat.ToJsonObj(t2s).ToRecord()
+	for _, key := range slices.Sorted(maps.Keys(e.Extra)) {
+		if slices.Contains(BasicStatCsvHeader, key) {
+			continue
+		}
+		rec = rec.Set(key, e.Extra[key])
+	}
+	return rec
+}
+
+func (e EnrichedStat) CheckKeys() error {
+	for _, key := range BasicStatCsvHeader {
+		_, found := e.Extra[key]
+		if found {
+			return fmt.Errorf("%w %q", ErrReservedKey, key)
+		}
+	}
+	return nil
+}
+
+func (e EnrichedStat) Merge(o EnrichedStat) (EnrichedStat, error) {
+	if 0 == len(o.Extra) {
+		return e, nil
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(o.Extra)) {
+		_, found := e.Extra[key]
+		if found {
+			return e, fmt.Errorf("%w %q", ErrDuplicateKey, key)
+		}
+	}
+
+	var merged map[string]any = maps.Clone(e.Extra)
+	if nil == merged {
+		merged = map[string]any{}
+	}
+	maps.Copy(merged, o.Extra)
+	e.Extra = merged
+	return e, nil
+}
+
+type Enricher interface {
+	Enrich(ctx context.Context, root Root, stat BasicStat) (EnrichedStat, error)
+}
+
+type EnricherFunc func(context.Context, Root, BasicStat) (EnrichedStat, error)
+
+func (f EnricherFunc) Enrich(
+	ctx context.Context,
+	root Root,
+	stat BasicStat,
+) (EnrichedStat, error) {
+	return f(ctx, root, stat)
+}
+
+type Enrichers []Enricher
+
+func (s Enrichers) Enrich(
+	ctx context.Context,
+	root Root,
+	stat BasicStat,
+) (EnrichedStat, error) {
+	var ret EnrichedStat = EnrichedStat{BasicStat: stat}
+	for _, enricher := range s {
+		enriched, e := enricher.Enrich(ctx, root, stat)
+		if nil == e {
+			e = enriched.CheckKeys()
+		}
+		if nil == e {
+			ret, e = ret.Merge(enriched)
+		}
+		if nil != e {
+			return ret, fmt.Errorf("%s: %w", stat.Path, e)
+		}
+	}
+	return ret, nil
+}
+
+func (s Enrichers) EnrichAll(
+	ctx context.Context,
+	root Root,
+	stats iter.Seq2[BasicStat, error],
+) iter.Seq2[EnrichedStat, error] {
+	return func(yield func(EnrichedStat, error) bool) {
+		for stat, e := range stats {
+			if nil != e {
+				yield(EnrichedStat{BasicStat: stat}, e)
+				return
+			}
+
+			enriched, e := s.Enrich(ctx, root, stat)
+			if !yield(enriched, e) {
+				return
+			}
+		}
+	}
+}
+
+func (s Enrichers) Close() error {
+	var errs []error
+	for _, enricher := range s {
+		closer, ok := enricher.(io.Closer)
+		if ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type EnricherRegistry struct {
+	mu        sync.RWMutex
+	enrichers map[string]Enricher
+}
+
+func NewEnricherRegistry() *EnricherRegistry {
+	return &EnricherRegistry{enrichers: map[string]Enricher{}}
+}
+
+func (r *EnricherRegistry) Register(name string, enricher Enricher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.enrichers[name] = enricher
+}
+
+func (r *EnricherRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Sorted(maps.Keys(r.enrichers))
+}
+
+func (r *EnricherRegistry) Lookup(names ...string) (Enrichers, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ret Enrichers = make(Enrichers, 0, len(names))
+	for _, name := range names {
+		enricher, found := r.enrichers[name]
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownEnricher, name)
+		}
+		ret = append(ret, enricher)
+	}
+	return ret, nil
+}
+
+type DescribedEnricher struct {
+	Enricher
+	Fields []FieldSchema
+}
+
+func (d DescribedEnricher) DescribeFields() []FieldSchema { return d.Fields }
+
+var enricherMime Enricher = EnricherFunc(
+	func(_ context.Context, _ Root, stat BasicStat) (EnrichedStat, error) {
+		var typ string = mime.TypeByExtension(path.Ext(stat.Path))
+		switch typ {
+		case "":
+			return EnrichedStat{BasicStat: stat}, nil
+		default:
+			return EnrichedStat{
+				BasicStat: stat,
+				Extra:     map[string]any{"mime_type": typ},
+			}, nil
+		}
+	},
+)
+
+var enricherSha256 Enricher = EnricherFunc(
+	func(ctx context.Context, root Root, stat BasicStat) (EnrichedStat, error) {
+		if FileTypeRglr != stat.FileType {
+			return EnrichedStat{BasicStat: stat}, nil
+		}
+
+		e := ctx.Err()
+		if nil != e {
+			return EnrichedStat{BasicStat: stat}, e
+		}
+
+		f, e := root.Root.Open(stat.Path)
+		if nil != e {
+			return EnrichedStat{BasicStat: stat}, e
+		}
+		defer f.Close()
+
+		var h hash.Hash = sha256.New()
+		_, e = io.Copy(h, f)
+		if nil != e {
+			return EnrichedStat{BasicStat: stat}, e
+		}
+
+		return EnrichedStat{
+			BasicStat: stat,
+			Extra:     map[string]any{"sha256": hex.EncodeToString(h.Sum(nil))},
+		}, nil
+	},
+)
+
+var EnricherMime Enricher = DescribedEnricher{
+	Enricher: enricherMime,
+	Fields:   []FieldSchema{{Key: "mime_type", Types: []string{"string"}}},
+}
+
+var EnricherSha256 Enricher = DescribedEnricher{
+	Enricher: enricherSha256,
+	Fields:   []FieldSchema{{Key: "sha256", Types: []string{"string"}}},
+}
+
+var EnricherRegistryDefault *EnricherRegistry = func() *EnricherRegistry {
+	var r *EnricherRegistry = NewEnricherRegistry()
+	r.Register("mime", EnricherMime)
+	r.Register("sha256", EnricherSha256)
+	r.Register("mode", EnricherMode)
+	return r
+}()
+
+func (c FileTypeToString) EnrichedStatsToWriter(
+	wtr io.Writer,
+) func(iter.Seq2[EnrichedStat, error]) error {
+	return func(stats iter.Seq2[EnrichedStat, error]) error {
+		return RecordsToWriter(wtr)(c.EnrichedStatsToRecords(stats))
+	}
+}
+
+func (c FileTypeToString) EnrichedStatsToStdout(
+	stats iter.Seq2[EnrichedStat, error],
+) error {
+	return c.EnrichedStatsToWriter(os.Stdout)(stats)
+}
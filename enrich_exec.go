@@ -0,0 +1,150 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+)
+
+var ErrEnricherExited error = errors.New("enricher process exited")
+
+func bytesToExtra(raw []byte) (map[string]any, error) {
+	var extra map[string]any
+	var trimmed []byte = bytes.TrimSpace(raw)
+	if 0 == len(trimmed) {
+		return nil, nil
+	}
+	e := json.Unmarshal(trimmed, &extra)
+	return extra, e
+}
+
+type ExecEnricher struct{ Command }
+
+func (x ExecEnricher) Enrich(
+	ctx context.Context,
+	root Root,
+	stat BasicStat,
+) (EnrichedStat, error) {
+	var ret EnrichedStat = EnrichedStat{BasicStat: stat}
+	if 0 == len(x.Command) {
+		return ret, ErrEmptyCommand
+	}
+
+	var args []string = slices.Concat(x.Command[1:], []string{stat.Path})
+	var cmd *exec.Cmd = exec.CommandContext(ctx, x.Command[0], args...)
+	cmd.Dir = root.Root.Name()
+	cmd.Stderr = os.Stderr
+
+	out, e := cmd.Output()
+	if nil != e {
+		return ret, e
+	}
+
+	ret.Extra, e = bytesToExtra(out)
+	return ret, e
+}
+
+type ExecStreamEnricher struct {
+	Command
+	FileTypeToString
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout *bufio.Reader
+}
+
+func (x *ExecStreamEnricher) start(ctx context.Context, root Root) error {
+	if 0 == len(x.Command) {
+		return ErrEmptyCommand
+	}
+
+	var cmd *exec.Cmd = exec.CommandContext(ctx, x.Command[0], x.Command[1:]...)
+	cmd.Dir = root.Root.Name()
+	cmd.Stderr = os.Stderr
+
+	stdin, e := cmd.StdinPipe()
+	if nil != e {
+		return e
+	}
+
+	stdout, e := cmd.StdoutPipe()
+	if nil != e {
+		return e
+	}
+
+	e = cmd.Start()
+	if nil != e {
+		return e
+	}
+
+	x.cmd = cmd
+	x.stdin = stdin
+	x.stdout = bufio.NewReader(stdout)
+	return nil
+}
+
+func (x *ExecStreamEnricher) Enrich(
+	ctx context.Context,
+	root Root,
+	stat BasicStat,
+) (EnrichedStat, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	var ret EnrichedStat = EnrichedStat{BasicStat: stat}
+
+	if nil == x.cmd {
+		e := x.start(ctx, root)
+		if nil != e {
+			return ret, e
+		}
+	}
+
+	var t2s FileTypeToString = x.FileTypeToString
+	if nil == t2s {
+		t2s = FileTypeToStringDefault
+	}
+
+	req, e := json.Marshal(stat.ToJsonObj(t2s))
+	if nil != e {
+		return ret, e
+	}
+
+	_, e = x.stdin.Write(append(req, '\n'))
+	if nil != e {
+		return ret, e
+	}
+
+	line, e := x.stdout.ReadBytes('\n')
+	if errors.Is(e, io.EOF) && 0 == len(line) {
+		return ret, ErrEnricherExited
+	}
+	if nil != e && !errors.Is(e, io.EOF) {
+		return ret, e
+	}
+
+	ret.Extra, e = bytesToExtra(line)
+	return ret, e
+}
+
+func (x *ExecStreamEnricher) Close() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if nil == x.cmd {
+		return nil
+	}
+
+	var ce error = x.stdin.Close()
+	var we error = x.cmd.Wait()
+	x.cmd = nil
+	return errors.Join(ce, we)
+}
@@ -0,0 +1,95 @@
+package names2stats
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var ErrEmptyCommand error = errors.New("empty command")
+
+type Command []string
+
+func CommandLineToCommand(line string) Command {
+	return strings.Fields(line)
+}
+
+func (c Command) ToNames(ctx context.Context) NameIter {
+	return c.ToNamesDelim(ctx, NameDelimNewline)
+}
+
+func (c Command) ToNamesDelim(ctx context.Context, d NameDelim) NameIter {
+	return func(yield func(string, error) bool) {
+		if 0 == len(c) {
+			yield("", ErrEmptyCommand)
+			return
+		}
+
+		cctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var cmd *exec.Cmd = exec.CommandContext(cctx, c[0], c[1:]...)
+		cmd.Stderr = os.Stderr
+
+		stdout, e := cmd.StdoutPipe()
+		if nil != e {
+			yield("", e)
+			return
+		}
+
+		e = cmd.Start()
+		if nil != e {
+			yield("", e)
+			return
+		}
+
+		for name, e := range d.ReaderToNameIter(stdout) {
+			if !yield(name, e) || nil != e {
+				cancel()
+				_ = cmd.Wait()
+				return
+			}
+		}
+
+		e = cmd.Wait()
+		if nil == e {
+			e = ctx.Err()
+		}
+		if nil != e {
+			yield("", e)
+		}
+	}
+}
+
+type commandStdin struct {
+	io.WriteCloser
+	cmd *exec.Cmd
+}
+
+func (c commandStdin) Close() error {
+	return errors.Join(c.WriteCloser.Close(), c.cmd.Wait())
+}
+
+func (c Command) StdinWriter(ctx context.Context) (io.WriteCloser, error) {
+	if 0 == len(c) {
+		return nil, ErrEmptyCommand
+	}
+
+	var cmd *exec.Cmd = exec.CommandContext(ctx, c[0], c[1:]...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	stdin, e := cmd.StdinPipe()
+	if nil != e {
+		return nil, e
+	}
+
+	e = cmd.Start()
+	if nil != e {
+		return nil, e
+	}
+	return commandStdin{WriteCloser: stdin, cmd: cmd}, nil
+}
@@ -0,0 +1,79 @@
+package exitcode
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+type Code int
+
+const (
+	OK       Code = 0
+	Failure  Code = 1
+	Config   Code = 2
+	RootOpen Code = 3
+	Partial  Code = 4
+	Sink     Code = 5
+	Locked   Code = 6
+	Quota    Code = 7
+	Signal   Code = 130
+)
+
+var codeNames map[Code]string = map[Code]string{
+	OK:       "ok",
+	Failure:  "failure",
+	Config:   "config",
+	RootOpen: "root-open",
+	Partial:  "partial",
+	Sink:     "sink",
+	Locked:   "locked",
+	Quota:    "quota",
+	Signal:   "signal",
+}
+
+func (c Code) String() string {
+	name, found := codeNames[c]
+	switch found {
+	case true:
+		return name
+	default:
+		return "unknown"
+	}
+}
+
+type ClassifiedError struct {
+	Code
+	Err error
+}
+
+func (e ClassifiedError) Error() string { return e.Err.Error() }
+
+func (e ClassifiedError) Unwrap() error { return e.Err }
+
+func Wrap(c Code, e error) error {
+	switch e {
+	case nil:
+		return nil
+	default:
+		return ClassifiedError{Code: c, Err: e}
+	}
+}
+
+func Classify(e error) Code {
+	if nil == e {
+		return OK
+	}
+
+	var classified ClassifiedError
+	if errors.As(e, &classified) {
+		return classified.Code
+	}
+
+	if errors.Is(e, context.Canceled) {
+		return Signal
+	}
+	return Failure
+}
+
+func Exit(e error) { os.Exit(int(Classify(e))) }
@@ -0,0 +1,451 @@
+package names2stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var (
+	ErrExprSyntax   error = errors.New("expression syntax error")
+	ErrExprType     error = errors.New("expression type error")
+	ErrExprUnknown  error = errors.New("unknown identifier")
+	ErrExprArgCount error = errors.New("wrong number of arguments")
+	ErrExprName     error = errors.New("invalid computed field name")
+)
+
+type ExprEnv map[string]any
+
+type Expr func(ExprEnv) (any, error)
+
+type exprFunc func(args []any) (any, error)
+
+func exprNum(v any) (float64, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return 0, fmt.Errorf("%w: %v is not a number", ErrExprType, v)
+	}
+	return f, nil
+}
+
+func exprStr(v any) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %v is not a string", ErrExprType, v)
+	}
+	return s, nil
+}
+
+func strFunc(f func(string) string) exprFunc {
+	return func(args []any) (any, error) {
+		if 1 != len(args) {
+			return nil, ErrExprArgCount
+		}
+		s, e := exprStr(args[0])
+		if nil != e {
+			return nil, e
+		}
+		return f(s), nil
+	}
+}
+
+func numFunc(f func(float64) float64) exprFunc {
+	return func(args []any) (any, error) {
+		if 1 != len(args) {
+			return nil, ErrExprArgCount
+		}
+		n, e := exprNum(args[0])
+		if nil != e {
+			return nil, e
+		}
+		return f(n), nil
+	}
+}
+
+var exprFuncs map[string]exprFunc = map[string]exprFunc{
+	"pathext":  strFunc(path.Ext),
+	"basename": strFunc(path.Base),
+	"dirname":  strFunc(path.Dir),
+	"lower":    strFunc(strings.ToLower),
+	"upper":    strFunc(strings.ToUpper),
+	"floor":    numFunc(math.Floor),
+	"round":    numFunc(math.Round),
+	"days":     numFunc(func(sec float64) float64 { return sec / 86400 }),
+	"hours":    numFunc(func(sec float64) float64 { return sec / 3600 }),
+	"len": func(args []any) (any, error) {
+		if 1 != len(args) {
+			return nil, ErrExprArgCount
+		}
+		s, e := exprStr(args[0])
+		return float64(len(s)), e
+	},
+}
+
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *exprParser) peek() byte {
+	p.skipSpace()
+	if len(p.src) <= p.pos {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *exprParser) syntaxErr(msg string) error {
+	return fmt.Errorf("%w: %s at %d in %q", ErrExprSyntax, msg, p.pos, p.src)
+}
+
+func binaryOp(op byte, l Expr, r Expr) Expr {
+	return func(env ExprEnv) (any, error) {
+		lv, e := l(env)
+		if nil != e {
+			return nil, e
+		}
+		rv, e := r(env)
+		if nil != e {
+			return nil, e
+		}
+
+		ls, lstr := lv.(string)
+		rs, rstr := rv.(string)
+		if '+' == op && lstr && rstr {
+			return ls + rs, nil
+		}
+
+		ln, e := exprNum(lv)
+		if nil != e {
+			return nil, e
+		}
+		rn, e := exprNum(rv)
+		if nil != e {
+			return nil, e
+		}
+
+		switch op {
+		case '+':
+			return ln + rn, nil
+		case '-':
+			return ln - rn, nil
+		case '*':
+			return ln * rn, nil
+		case '/', '%':
+			if 0 == rn {
+				return nil, fmt.Errorf("%w: %c by zero", ErrExprType, op)
+			}
+		}
+
+		switch op {
+		case '/':
+			return ln / rn, nil
+		default:
+			return math.Mod(ln, rn), nil
+		}
+	}
+}
+
+func (p *exprParser) parseExpr() (Expr, error) {
+	left, e := p.parseTerm()
+	if nil != e {
+		return nil, e
+	}
+	for {
+		var op byte = p.peek()
+		if '+' != op && '-' != op {
+			return left, nil
+		}
+		p.pos++
+		right, e := p.parseTerm()
+		if nil != e {
+			return nil, e
+		}
+		left = binaryOp(op, left, right)
+	}
+}
+
+func (p *exprParser) parseTerm() (Expr, error) {
+	left, e := p.parseUnary()
+	if nil != e {
+		return nil, e
+	}
+	for {
+		var op byte = p.peek()
+		if '*' != op && '/' != op && '%' != op {
+			return left, nil
+		}
+		p.pos++
+		right, e := p.parseUnary()
+		if nil != e {
+			return nil, e
+		}
+		left = binaryOp(op, left, right)
+	}
+}
+
+func (p *exprParser) parseUnary() (Expr, error) {
+	if '-' != p.peek() {
+		return p.parsePrimary()
+	}
+	p.pos++
+	operand, e := p.parseUnary()
+	if nil != e {
+		return nil, e
+	}
+	return binaryOp('-', func(ExprEnv) (any, error) { return 0.0, nil }, operand), nil
+}
+
+func isIdentByte(b byte, first bool) bool {
+	switch {
+	case '_' == b:
+		return true
+	case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z':
+		return true
+	case '0' <= b && b <= '9':
+		return !first
+	default:
+		return false
+	}
+}
+
+func (p *exprParser) parsePrimary() (Expr, error) {
+	var c byte = p.peek()
+	switch {
+	case 0 == c:
+		return nil, p.syntaxErr("unexpected end")
+	case '(' == c:
+		p.pos++
+		inner, e := p.parseExpr()
+		if nil != e {
+			return nil, e
+		}
+		if ')' != p.peek() {
+			return nil, p.syntaxErr("missing )")
+		}
+		p.pos++
+		return inner, nil
+	case '"' == c:
+		return p.parseString()
+	case '.' == c || ('0' <= c && c <= '9'):
+		return p.parseNumber()
+	case isIdentByte(c, true):
+		return p.parseIdent()
+	default:
+		return nil, p.syntaxErr("unexpected " + strconv.QuoteRune(rune(c)))
+	}
+}
+
+func (p *exprParser) parseString() (Expr, error) {
+	var start int = p.pos
+	for p.pos++; p.pos < len(p.src); p.pos++ {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			s, e := strconv.Unquote(p.src[start:p.pos])
+			if nil != e {
+				return nil, p.syntaxErr(e.Error())
+			}
+			return func(ExprEnv) (any, error) { return s, nil }, nil
+		}
+	}
+	return nil, p.syntaxErr("unterminated string")
+}
+
+func (p *exprParser) parseNumber() (Expr, error) {
+	var start int = p.pos
+	for p.pos < len(p.src) && 0 <= strings.IndexByte("0123456789.", p.src[p.pos]) {
+		p.pos++
+	}
+	n, e := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if nil != e {
+		return nil, p.syntaxErr(e.Error())
+	}
+	return func(ExprEnv) (any, error) { return n, nil }, nil
+}
+
+func (p *exprParser) parseIdent() (Expr, error) {
+	var start int = p.pos
+	for p.pos < len(p.src) && isIdentByte(p.src[p.pos], false) {
+		p.pos++
+	}
+	var name string = p.src[start:p.pos]
+
+	if '(' != p.peek() {
+		return func(env ExprEnv) (any, error) {
+			val, found := env[name]
+			if !found {
+				return nil, fmt.Errorf("%w: %s", ErrExprUnknown, name)
+			}
+			return val, nil
+		}, nil
+	}
+	p.pos++
+
+	f, found := exprFuncs[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s()", ErrExprUnknown, name)
+	}
+
+	var args []Expr
+	for ')' != p.peek() {
+		if 0 < len(args) {
+			if ',' != p.peek() {
+				return nil, p.syntaxErr("missing ,")
+			}
+			p.pos++
+		}
+		arg, e := p.parseExpr()
+		if nil != e {
+			return nil, e
+		}
+		args = append(args, arg)
+	}
+	p.pos++
+
+	return func(env ExprEnv) (any, error) {
+		var vals []any = make([]any, 0, len(args))
+		for _, arg := range args {
+			v, e := arg(env)
+			if nil != e {
+				return nil, e
+			}
+			vals = append(vals, v)
+		}
+		return f(vals)
+	}, nil
+}
+
+func ParseExpr(src string) (Expr, error) {
+	var p exprParser = exprParser{src: src}
+	expr, e := p.parseExpr()
+	if nil != e {
+		return nil, e
+	}
+	if 0 != p.peek() {
+		return nil, p.syntaxErr("trailing input")
+	}
+	return expr, nil
+}
+
+type ComputedField struct {
+	Name string
+	Expr
+}
+
+type ComputedFields struct {
+	Fields []ComputedField
+	FileTypeToString
+	Now time.Time
+}
+
+var exprReservedNames []string = []string{
+	"path",
+	"size",
+	"modified",
+	"modified_time",
+	"file_type",
+	"now",
+}
+
+func validComputedName(name string, defined []ComputedField) error {
+	switch {
+	case "" == name:
+		return fmt.Errorf("%w: empty name", ErrExprName)
+	case slices.Contains(exprReservedNames, name):
+		return fmt.Errorf("%w: %q is reserved", ErrExprName, name)
+	case slices.ContainsFunc(defined, func(f ComputedField) bool {
+		return name == f.Name
+	}):
+		return fmt.Errorf("%w: duplicate %q", ErrExprName, name)
+	default:
+		return nil
+	}
+}
+
+func ParseComputedFields(defs string) ([]ComputedField, error) {
+	var ret []ComputedField
+	for _, def := range strings.Split(defs, ";") {
+		if "" == strings.TrimSpace(def) {
+			continue
+		}
+
+		name, src, found := strings.Cut(def, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: missing = in %q", ErrExprSyntax, def)
+		}
+
+		name = strings.TrimSpace(name)
+		e := validComputedName(name, ret)
+		if nil != e {
+			return nil, e
+		}
+
+		expr, e := ParseExpr(src)
+		if nil != e {
+			return nil, e
+		}
+
+		ret = append(ret, ComputedField{Name: name, Expr: expr})
+	}
+	return ret, nil
+}
+
+func (b BasicStat) ToExprEnv(t2s FileTypeToString, now time.Time) ExprEnv {
+	var unixSec func(time.Time) float64 = func(t time.Time) float64 {
+		return float64(t.UnixMicro()) / 1e6
+	}
+	return ExprEnv{
+		"path":      b.Path,
+		"size":      float64(b.Size),
+		"modified":  unixSec(b.Modified.ToTime()),
+		"file_type": t2s(b.FileType),
+		"now":       unixSec(now),
+	}
+}
+
+func (c ComputedFields) Enrich(
+	_ context.Context,
+	_ Root,
+	stat BasicStat,
+) (EnrichedStat, error) {
+	var ret EnrichedStat = EnrichedStat{
+		BasicStat: stat,
+		Extra:     make(map[string]any, len(c.Fields)),
+	}
+
+	var t2s FileTypeToString = c.FileTypeToString
+	if nil == t2s {
+		t2s = FileTypeToStringDefault
+	}
+
+	var env ExprEnv = stat.ToExprEnv(t2s, c.Now)
+	for _, f := range c.Fields {
+		val, e := f.Expr(env)
+		n, isNum := val.(float64)
+		if nil == e && isNum && (math.IsInf(n, 0) || math.IsNaN(n)) {
+			e = fmt.Errorf("%w: %v is not a finite number", ErrExprType, n)
+		}
+		if nil != e {
+			return ret, fmt.Errorf("%s: %w", f.Name, e)
+		}
+		ret.Extra[f.Name] = val
+		env[f.Name] = val
+	}
+	return ret, nil
+}
@@ -0,0 +1,82 @@
+package names2stats
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+)
+
+var ErrAlreadySeen error = errors.New("file already emitted")
+
+type FileID struct {
+	Device uint64
+	Inode  uint64
+}
+
+type FileIDSet map[FileID]struct{}
+
+func (s FileIDSet) Insert(id FileID) (inserted bool) {
+	_, found := s[id]
+	if found {
+		return false
+	}
+	s[id] = struct{}{}
+	return true
+}
+
+type InfoCheck func(fs.FileInfo) error
+
+func NewOnceCheck() InfoCheck {
+	var seen FileIDSet = FileIDSet{}
+	var mu sync.Mutex
+	return func(fi fs.FileInfo) error {
+		id, found := FileInfoToFileID(fi)
+		if !found {
+			return nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if !seen.Insert(id) {
+			return ErrAlreadySeen
+		}
+		return nil
+	}
+}
+
+func (r Root) ToFilenameToBasicStatChecked(checks ...InfoCheck) FilenameToBasicStat {
+	return func(fullpath string) (BasicStat, error) {
+		var empty BasicStat
+
+		fi, e := r.NameToInfo(fullpath)
+		if nil != e {
+			return empty, e
+		}
+
+		for _, check := range checks {
+			e = check(fi)
+			if nil != e {
+				return empty, e
+			}
+		}
+
+		return FileInfo{fi}.ToBasicStat().WithFullPath(fullpath), nil
+	}
+}
+
+func (r Root) ToFilenameToBasicStatOnce() FilenameToBasicStat {
+	return r.ToFilenameToBasicStatChecked(NewOnceCheck())
+}
+
+func (i BasicStatIter) SkipErr(target error) BasicStatIter {
+	return func(yield func(BasicStat, error) bool) {
+		for s, e := range i {
+			if nil != e && errors.Is(e, target) {
+				continue
+			}
+			if !yield(s, e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package names2stats
+
+import (
+	"io/fs"
+)
+
+func FileInfoToFileID(_ fs.FileInfo) (FileID, bool) {
+	return FileID{}, false
+}
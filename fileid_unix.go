@@ -0,0 +1,19 @@
+//go:build unix
+
+package names2stats
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+func FileInfoToFileID(fi fs.FileInfo) (FileID, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return FileID{}, false
+	}
+	return FileID{
+		Device: uint64(st.Dev),
+		Inode:  uint64(st.Ino),
+	}, true
+}
@@ -0,0 +1,186 @@
+package names2stats
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const globMeta string = `*?[\`
+
+type GlobPattern string
+
+func (p GlobPattern) ToSegments() []string {
+	var cleaned string = path.Clean(string(p))
+	return strings.Split(cleaned, "/")
+}
+
+func (p GlobPattern) Validate() error {
+	for _, seg := range p.ToSegments() {
+		_, e := path.Match(seg, "")
+		if nil != e {
+			return e
+		}
+	}
+	return nil
+}
+
+func (p GlobPattern) HasMeta() bool {
+	return strings.ContainsAny(string(p), globMeta)
+}
+
+func (p GlobPattern) HasDoubleStar() bool {
+	for _, seg := range p.ToSegments() {
+		if "**" == seg {
+			return true
+		}
+	}
+	return false
+}
+
+func (p GlobPattern) Base() string {
+	var segs []string = p.ToSegments()
+	var literal []string
+	for _, seg := range segs[:len(segs)-1] {
+		if strings.ContainsAny(seg, globMeta) {
+			break
+		}
+		literal = append(literal, seg)
+	}
+	switch len(literal) {
+	case 0:
+		return "."
+	default:
+		return path.Join(literal...)
+	}
+}
+
+func matchSegments(pat []string, name []string, c PathCase) (bool, error) {
+	for 0 < len(pat) {
+		if "**" == pat[0] {
+			var rest []string = pat[1:]
+			for i := 0; i <= len(name); i++ {
+				matched, e := matchSegments(rest, name[i:], c)
+				if nil != e || matched {
+					return matched, e
+				}
+			}
+			return false, nil
+		}
+
+		if 0 == len(name) {
+			return false, nil
+		}
+
+		matched, e := path.Match(c.Fold(pat[0]), c.Fold(name[0]))
+		if nil != e || !matched {
+			return false, e
+		}
+
+		pat = pat[1:]
+		name = name[1:]
+	}
+	return 0 == len(name), nil
+}
+
+func (p GlobPattern) Match(name string) (bool, error) {
+	return p.MatchCase(name, PathCaseSensitive)
+}
+
+func (p GlobPattern) MatchCase(name string, c PathCase) (bool, error) {
+	return matchSegments(p.ToSegments(), strings.Split(name, "/"), c)
+}
+
+func (p GlobPattern) walkBase(fsys fs.FS, c PathCase) string {
+	var base string = p.Base()
+	if PathCaseSensitive == c {
+		return base
+	}
+
+	_, e := fs.Stat(fsys, base)
+	switch e {
+	case nil:
+		return base
+	default:
+		return "."
+	}
+}
+
+func (p GlobPattern) Expand(fsys fs.FS) NameIter {
+	return p.ExpandCase(fsys, PathCaseSensitive)
+}
+
+func (p GlobPattern) ExpandCase(fsys fs.FS, c PathCase) NameIter {
+	return func(yield func(string, error) bool) {
+		e := p.Validate()
+		if nil != e {
+			yield("", e)
+			return
+		}
+
+		if !p.HasMeta() {
+			yield(path.Clean(string(p)), nil)
+			return
+		}
+
+		var segs []string = p.ToSegments()
+		var unlimited bool = p.HasDoubleStar()
+		var stopped bool
+
+		e = fs.WalkDir(
+			fsys,
+			p.walkBase(fsys, c),
+			func(name string, d fs.DirEntry, e error) error {
+				if nil != e {
+					return e
+				}
+
+				if "." == name {
+					return nil
+				}
+
+				var depth int = strings.Count(name, "/") + 1
+
+				matched, e := matchSegments(segs, strings.Split(name, "/"), c)
+				if nil != e {
+					return e
+				}
+
+				if matched && !yield(name, nil) {
+					stopped = true
+					return fs.SkipAll
+				}
+
+				if d.IsDir() && !unlimited && len(segs) == depth {
+					return fs.SkipDir
+				}
+
+				return nil
+			},
+		)
+
+		if nil != e && !stopped {
+			yield("", e)
+		}
+	}
+}
+
+func GlobsToNames(fsys fs.FS, patterns NameIter) NameIter {
+	return func(yield func(string, error) bool) {
+		for pat, e := range patterns {
+			if nil != e {
+				yield("", e)
+				return
+			}
+
+			for name, e := range GlobPattern(pat).Expand(fsys) {
+				if !yield(name, e) {
+					return
+				}
+				if nil != e {
+					return
+				}
+			}
+		}
+	}
+}
@@ -1,3 +1,9 @@
 module github.com/takanoriyanagitani/go-names2stats
 
-go 1.24.2
+go 1.25.0
+
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/tetratelabs/wazero v1.9.0
+	golang.org/x/sys v0.44.0
+)
@@ -0,0 +1,256 @@
+package names2stats
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	RecordTypeDirGrowth    string = "dir_growth"
+	RecordTypeGrowthPeriod string = "growth_period"
+)
+
+var ErrSnapshotOrder error = errors.New("snapshots not in chronological order")
+
+type Snapshot struct {
+	Time  time.Time
+	Stats map[string]BasicStat
+}
+
+type snapshotLine struct {
+	BasicStatJson
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+func ReadSnapshot(rdr io.Reader, fallback time.Time) (Snapshot, error) {
+	var snap Snapshot = Snapshot{Stats: map[string]BasicStat{}}
+	var header time.Time
+	var scanned time.Time
+
+	var n int
+	for raw, e := range ReaderToRawRecords(rdr) {
+		n++
+		if nil != e {
+			return snap, e
+		}
+
+		typ, e := RawRecordType(raw)
+		switch {
+		case nil != e:
+		case RecordTypeHeader == typ:
+			var h SnapshotHeader
+			e = json.Unmarshal(raw, &h)
+			header = h.StartTime
+		case "" == typ || RecordTypeStat == typ:
+			var line snapshotLine
+			e = json.Unmarshal(raw, &line)
+			var s BasicStat = line.ToBasicStat(StringToFileTypeDefault)
+			snap.Stats[path.Clean(s.Path)] = s
+			if scanned.Before(line.ScannedAt) {
+				scanned = line.ScannedAt
+			}
+		}
+		if nil != e {
+			return snap, fmt.Errorf("record %d: %w", n, e)
+		}
+	}
+
+	switch {
+	case !header.IsZero():
+		snap.Time = header
+	case !scanned.IsZero():
+		snap.Time = scanned
+	default:
+		snap.Time = fallback
+	}
+	return snap, nil
+}
+
+type DirGrowth struct {
+	Dir          string
+	AddedBytes   int64
+	RemovedBytes int64
+	AddedFiles   int64
+	RemovedFiles int64
+}
+
+func (g DirGrowth) NetBytes() int64 { return g.AddedBytes - g.RemovedBytes }
+
+func perDay(bytes int64, days float64) float64 {
+	if days <= 0 {
+		return 0
+	}
+	return float64(bytes) / days
+}
+
+func (g DirGrowth) ToRecord(days float64) Record {
+	return Record{
+		{Key: "record_type", Value: RecordTypeDirGrowth},
+		{Key: "dir", Value: g.Dir},
+		{Key: "added_bytes", Value: g.AddedBytes},
+		{Key: "removed_bytes", Value: g.RemovedBytes},
+		{Key: "net_bytes", Value: g.NetBytes()},
+		{Key: "added_files", Value: g.AddedFiles},
+		{Key: "removed_files", Value: g.RemovedFiles},
+		{Key: "added_bytes_per_day", Value: perDay(g.AddedBytes, days)},
+		{Key: "removed_bytes_per_day", Value: perDay(g.RemovedBytes, days)},
+		{Key: "net_bytes_per_day", Value: perDay(g.NetBytes(), days)},
+	}
+}
+
+func dirDepth(dir string) int {
+	if "." == dir {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+type SnapshotDiff struct {
+	Old  Snapshot
+	New  Snapshot
+	Case PathCase
+	Dirs map[string]*DirGrowth
+}
+
+func (d *SnapshotDiff) attribute(name string, added int64, removed int64, files int64) {
+	for dir := path.Dir(name); ; dir = path.Dir(dir) {
+		var key string = d.Case.Fold(dir)
+		g, found := d.Dirs[key]
+		if !found {
+			g = &DirGrowth{Dir: dir}
+			d.Dirs[key] = g
+		}
+		g.AddedBytes += added
+		g.RemovedBytes += removed
+		switch {
+		case 0 < files:
+			g.AddedFiles += files
+		case files < 0:
+			g.RemovedFiles -= files
+		}
+		if "." == dir || "/" == dir {
+			return
+		}
+	}
+}
+
+func (c PathCase) foldStats(stats map[string]BasicStat) map[string]BasicStat {
+	if PathCaseSensitive == c {
+		return stats
+	}
+
+	var ret map[string]BasicStat = make(map[string]BasicStat, len(stats))
+	for name, s := range stats {
+		ret[c.Fold(name)] = s
+	}
+	return ret
+}
+
+func NewSnapshotDiff(older Snapshot, newer Snapshot) (*SnapshotDiff, error) {
+	return NewSnapshotDiffCase(older, newer, PathCaseSensitive)
+}
+
+func NewSnapshotDiffCase(
+	older Snapshot,
+	newer Snapshot,
+	c PathCase,
+) (*SnapshotDiff, error) {
+	if newer.Time.Before(older.Time) {
+		return nil, fmt.Errorf(
+			"%w: %s > %s",
+			ErrSnapshotOrder,
+			older.Time.Format(time.RFC3339),
+			newer.Time.Format(time.RFC3339),
+		)
+	}
+
+	var d *SnapshotDiff = &SnapshotDiff{
+		Old:  older,
+		New:  newer,
+		Case: c,
+		Dirs: map[string]*DirGrowth{},
+	}
+	var olderFolded map[string]BasicStat = c.foldStats(older.Stats)
+	var newerFolded map[string]BasicStat = c.foldStats(newer.Stats)
+
+	// sorted, so that a directory spelled in several cases is reported
+	// with the same spelling on every run
+	for _, name := range slices.Sorted(maps.Keys(newer.Stats)) {
+		var s BasicStat = newer.Stats[name]
+		if FileTypeFldr == s.FileType {
+			continue
+		}
+		prev, found := olderFolded[c.Fold(name)]
+		switch {
+		case !found:
+			d.attribute(name, s.Size, 0, 1)
+		case prev.Size < s.Size:
+			d.attribute(name, s.Size-prev.Size, 0, 0)
+		case s.Size < prev.Size:
+			d.attribute(name, 0, prev.Size-s.Size, 0)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(older.Stats)) {
+		var prev BasicStat = older.Stats[name]
+		if FileTypeFldr == prev.FileType {
+			continue
+		}
+		_, found := newerFolded[c.Fold(name)]
+		if !found {
+			d.attribute(name, 0, prev.Size, -1)
+		}
+	}
+
+	return d, nil
+}
+
+func (d *SnapshotDiff) Days() float64 {
+	return d.New.Time.Sub(d.Old.Time).Hours() / 24
+}
+
+func (d *SnapshotDiff) Growth(maxDepth int) []DirGrowth {
+	var ret []DirGrowth
+	for _, g := range d.Dirs {
+		if 0 < maxDepth && maxDepth < dirDepth(g.Dir) {
+			continue
+		}
+		ret = append(ret, *g)
+	}
+	slices.SortFunc(ret, func(a, b DirGrowth) int {
+		return cmp.Or(
+			cmp.Compare(b.NetBytes(), a.NetBytes()),
+			cmp.Compare(a.Dir, b.Dir),
+		)
+	})
+	return ret
+}
+
+func (d *SnapshotDiff) Records(maxDepth int) RecordIter {
+	return func(yield func(Record, error) bool) {
+		var days float64 = d.Days()
+		var period Record = Record{
+			{Key: "record_type", Value: RecordTypeGrowthPeriod},
+			{Key: "old_time", Value: d.Old.Time},
+			{Key: "new_time", Value: d.New.Time},
+			{Key: "days", Value: days},
+		}
+		if !yield(period, nil) {
+			return
+		}
+
+		for _, g := range d.Growth(maxDepth) {
+			if !yield(g.ToRecord(days), nil) {
+				return
+			}
+		}
+	}
+}
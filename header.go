@@ -0,0 +1,84 @@
+package names2stats
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+)
+
+const RecordTypeHeader string = "header"
+
+const SchemaVersionCurrent int = 1
+
+type SnapshotHeader struct {
+	RecordType    string    `json:"record_type"`
+	SchemaVersion int       `json:"schema_version"`
+	Hostname      string    `json:"hostname"`
+	Root          string    `json:"root"`
+	StartTime     time.Time `json:"start_time"`
+	ToolVersion   string    `json:"tool_version"`
+	ConfigHash    string    `json:"config_hash"`
+}
+
+func ToolVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	return info.Main.Version
+}
+
+func ConfigHash(settings []string) string {
+	var sorted []string = slices.Sorted(slices.Values(settings))
+	var sum [sha256.Size]byte = sha256.Sum256([]byte(strings.Join(sorted, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+func NewSnapshotHeader(root string, settings []string) SnapshotHeader {
+	hostname, _ := os.Hostname()
+
+	abs, e := filepath.Abs(root)
+	if nil != e {
+		abs = root
+	}
+
+	return SnapshotHeader{
+		RecordType:    RecordTypeHeader,
+		SchemaVersion: SchemaVersionCurrent,
+		Hostname:      hostname,
+		Root:          abs,
+		StartTime:     time.Now(),
+		ToolVersion:   ToolVersion(),
+		ConfigHash:    ConfigHash(settings),
+	}
+}
+
+func (h SnapshotHeader) ToRecord() Record {
+	return Record{
+		{Key: "record_type", Value: h.RecordType},
+		{Key: "schema_version", Value: h.SchemaVersion},
+		{Key: "hostname", Value: h.Hostname},
+		{Key: "root", Value: h.Root},
+		{Key: "start_time", Value: h.StartTime},
+		{Key: "tool_version", Value: h.ToolVersion},
+		{Key: "config_hash", Value: h.ConfigHash},
+	}
+}
+
+func (i RecordIter) Prepend(first Record) RecordIter {
+	return func(yield func(Record, error) bool) {
+		if !yield(first, nil) {
+			return
+		}
+		for rec, e := range i {
+			if !yield(rec, e) {
+				return
+			}
+		}
+	}
+}
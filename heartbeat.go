@@ -0,0 +1,91 @@
+package names2stats
+
+import (
+	"context"
+	"time"
+)
+
+const RecordTypeHeartbeat string = "heartbeat"
+
+type Heartbeat struct {
+	RecordType       string    `json:"record_type"`
+	Time             time.Time `json:"time"`
+	RecordsSinceLast int64     `json:"records_since_last"`
+	LagMs            int64     `json:"lag_ms"`
+}
+
+func (h Heartbeat) ToRecord() Record {
+	return Record{
+		{Key: "record_type", Value: h.RecordType},
+		{Key: "time", Value: h.Time},
+		{Key: "records_since_last", Value: h.RecordsSinceLast},
+		{Key: "lag_ms", Value: h.LagMs},
+	}
+}
+
+type recordOrErr struct {
+	rec Record
+	err error
+}
+
+func (i RecordIter) WithHeartbeat(
+	ctx context.Context,
+	interval time.Duration,
+) RecordIter {
+	return func(yield func(Record, error) bool) {
+		var done chan struct{} = make(chan struct{})
+		var items chan recordOrErr = make(chan recordOrErr)
+		defer func() {
+			close(done)
+			for range items {
+			}
+		}()
+
+		go func() {
+			defer close(items)
+			for rec, e := range i {
+				select {
+				case items <- recordOrErr{rec: rec, err: e}:
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		var ticker *time.Ticker = time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var since int64
+		var last time.Time = time.Now()
+		for {
+			select {
+			case <-ctx.Done():
+				yield(nil, ctx.Err())
+				return
+			case now := <-ticker.C:
+				var beat Heartbeat = Heartbeat{
+					RecordType:       RecordTypeHeartbeat,
+					Time:             now,
+					RecordsSinceLast: since,
+					LagMs:            now.Sub(last).Milliseconds(),
+				}
+				since = 0
+				if !yield(beat.ToRecord(), nil) {
+					return
+				}
+			case item, ok := <-items:
+				if !ok {
+					return
+				}
+				if !yield(item.rec, item.err) {
+					return
+				}
+				if nil != item.err {
+					return
+				}
+				since++
+				last = time.Now()
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package names2stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	ansiReset  string = "\x1b[0m"
+	ansiBold   string = "\x1b[1m"
+	ansiDim    string = "\x1b[2m"
+	ansiRed    string = "\x1b[31m"
+	ansiYellow string = "\x1b[33m"
+	ansiBlue   string = "\x1b[34m"
+	ansiPurple string = "\x1b[35m"
+	ansiCyan   string = "\x1b[36m"
+)
+
+var humanTypeColors map[string]string = map[string]string{
+	fileTypeToStringMap[FileTypeFldr]: ansiBold + ansiBlue,
+	fileTypeToStringMap[FileTypeSyml]: ansiCyan,
+	fileTypeToStringMap[FileTypePipe]: ansiYellow,
+	fileTypeToStringMap[FileTypeSock]: ansiPurple,
+	fileTypeToStringMap[FileTypeChar]: ansiBold + ansiYellow,
+	fileTypeToStringMap[FileTypeBlck]: ansiBold + ansiYellow,
+}
+
+func HumanSize(size int64) string {
+	const unit int64 = 1024
+	if size < unit {
+		return fmt.Sprintf("%d", size)
+	}
+
+	var div int64 = unit
+	var exp int
+	for n := size / unit; unit <= n; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func IsTerminal(f *os.File) bool {
+	fi, e := f.Stat()
+	return nil == e && 0 != fi.Mode()&os.ModeCharDevice
+}
+
+func ColorAllowed() bool {
+	_, found := os.LookupEnv("NO_COLOR")
+	return !found
+}
+
+func humanValue(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+
+	raw, e := json.Marshal(val)
+	if nil != e {
+		return fmt.Sprint(val)
+	}
+	return string(raw)
+}
+
+type HumanRenderer struct{ Color bool }
+
+func (h HumanRenderer) paint(color string, s string) string {
+	if !h.Color || "" == color {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+func (h HumanRenderer) sizeColor(size int64) string {
+	switch {
+	case 1<<30 <= size:
+		return ansiBold + ansiRed
+	case 1<<20 <= size:
+		return ansiYellow
+	default:
+		return ""
+	}
+}
+
+func (h HumanRenderer) Render(rec Record) string {
+	var typ any
+	typ, _ = rec.Get("record_type")
+	if nil != typ && RecordTypeStat != typ {
+		var parts []string = make([]string, 0, len(rec))
+		for _, f := range rec {
+			parts = append(parts, f.Key+"="+humanValue(f.Value))
+		}
+		return h.paint(ansiDim, "# "+strings.Join(parts, " "))
+	}
+
+	var modified string
+	var size string
+	var name string
+	var color string
+	var extra []string
+	for _, f := range rec {
+		switch f.Key {
+		case "path":
+			name = fmt.Sprint(f.Value)
+		case "size":
+			n, _ := f.Value.(int64)
+			size = h.paint(h.sizeColor(n), fmt.Sprintf("%7s", HumanSize(n)))
+		case "modified_time":
+			t, _ := f.Value.(time.Time)
+			modified = t.Local().Format("2006-01-02 15:04")
+		case "file_type":
+			color = humanTypeColors[fmt.Sprint(f.Value)]
+		default:
+			extra = append(extra, f.Key+"="+humanValue(f.Value))
+		}
+	}
+
+	var line string = fmt.Sprintf("%s %s %s", modified, size, h.paint(color, name))
+	if 0 < len(extra) {
+		line += "  " + h.paint(ansiDim, strings.Join(extra, " "))
+	}
+	return line
+}
+
+func (h HumanRenderer) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			_, e = bw.WriteString(h.Render(rec) + "\n")
+			e = errors.Join(e, bw.Flush())
+			if nil != e {
+				return e
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,144 @@
+package names2stats
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MeasurementDefault string = "file_stat"
+
+func ScannedAt(now func() time.Time) RecordMapper {
+	return func(rec Record) Record {
+		if !IsStatRecord(rec) {
+			return rec
+		}
+		return rec.Set("scanned_at", now())
+	}
+}
+
+var (
+	influxNameEscaper *strings.Replacer = strings.NewReplacer(
+		",", "\\,",
+		" ", "\\ ",
+		"\n", "\\n",
+	)
+	influxTagEscaper *strings.Replacer = strings.NewReplacer(
+		",", "\\,",
+		"=", "\\=",
+		" ", "\\ ",
+		"\n", "\\n",
+	)
+	influxStrEscaper *strings.Replacer = strings.NewReplacer(
+		"\\", "\\\\",
+		"\"", "\\\"",
+		"\n", "\\n",
+	)
+)
+
+func influxField(val any) (string, bool) {
+	switch v := val.(type) {
+	case nil:
+		return "", false
+	case bool:
+		return strconv.FormatBool(v), true
+	case int64:
+		return strconv.FormatInt(v, 10) + "i", true
+	case int:
+		return strconv.Itoa(v) + "i", true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case time.Time:
+		return strconv.FormatInt(v.Unix(), 10) + "i", true
+	default:
+		return "\"" + influxStrEscaper.Replace(CsvValue(v)) + "\"", true
+	}
+}
+
+type InfluxOptions struct {
+	Measurement string
+	Tags        []string
+	Now         func() time.Time
+}
+
+func (o InfluxOptions) Line(rec Record) string {
+	var sb strings.Builder
+	sb.WriteString(influxNameEscaper.Replace(o.Measurement))
+
+	pathVal, _ := rec.Get("path")
+	fileType, _ := rec.Get("file_type")
+	var tags Record = Record{
+		{Key: "dir", Value: path.Dir(CsvValue(pathVal))},
+		{Key: "file_type", Value: fileType},
+	}
+
+	var ts time.Time = o.Now()
+	var fields []string
+	for _, f := range rec {
+		switch {
+		case "file_type" == f.Key:
+		case "scanned_at" == f.Key:
+			t, ok := f.Value.(time.Time)
+			if ok {
+				ts = t
+			}
+		case slices.Contains(o.Tags, f.Key):
+			tags = append(tags, f)
+		default:
+			val, ok := influxField(f.Value)
+			if ok {
+				fields = append(fields, influxTagEscaper.Replace(f.Key)+"="+val)
+			}
+		}
+	}
+
+	for _, t := range tags {
+		var val string = CsvValue(t.Value)
+		if "" == val {
+			continue
+		}
+		sb.WriteString(",")
+		sb.WriteString(influxTagEscaper.Replace(t.Key))
+		sb.WriteString("=")
+		sb.WriteString(influxTagEscaper.Replace(val))
+	}
+
+	sb.WriteString(" ")
+	sb.WriteString(strings.Join(fields, ","))
+	sb.WriteString(" ")
+	sb.WriteString(strconv.FormatInt(ts.UnixNano(), 10))
+	return sb.String()
+}
+
+func (o InfluxOptions) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	if "" == o.Measurement {
+		o.Measurement = MeasurementDefault
+	}
+	if nil == o.Now {
+		o.Now = time.Now
+	}
+
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			if !IsStatRecord(rec) {
+				continue
+			}
+
+			_, e = bw.WriteString(o.Line(rec) + "\n")
+			if nil != e {
+				return e
+			}
+		}
+		return bw.Flush()
+	}
+}
@@ -0,0 +1,136 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+type NameIter iter.Seq2[string, error]
+
+func NamesToNameIter(names iter.Seq[string]) NameIter {
+	return func(yield func(string, error) bool) {
+		for name := range names {
+			if !yield(name, nil) {
+				return
+			}
+		}
+	}
+}
+
+var (
+	magicGzip []byte = []byte{0x1f, 0x8b}
+	magicZstd []byte = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+func ReaderToDecompressed(rdr io.Reader) (io.ReadCloser, error) {
+	var br *bufio.Reader = bufio.NewReader(rdr)
+
+	head, e := br.Peek(len(magicZstd))
+	if nil != e && !errors.Is(e, io.EOF) {
+		return nil, e
+	}
+
+	switch {
+	case bytes.HasPrefix(head, magicGzip):
+		return gzip.NewReader(br)
+	case bytes.HasPrefix(head, magicZstd):
+		dec, e := zstd.NewReader(br)
+		if nil != e {
+			return nil, e
+		}
+		return dec.IOReadCloser(), nil
+	default:
+		return io.NopCloser(br), nil
+	}
+}
+
+type InputPath string
+
+func (p InputPath) ToNames() NameIter { return p.ToNamesDelim(NameDelimNewline) }
+
+func (p InputPath) ToNamesDelim(d NameDelim) NameIter {
+	return func(yield func(string, error) bool) {
+		f, e := os.Open(string(p))
+		if nil != e {
+			yield("", e)
+			return
+		}
+		defer f.Close()
+
+		dec, e := ReaderToDecompressed(f)
+		if nil != e {
+			yield("", e)
+			return
+		}
+		defer dec.Close()
+
+		for name, e := range d.ReaderToNameIter(dec) {
+			if !yield(name, e) || nil != e {
+				return
+			}
+		}
+	}
+}
+
+type InputPaths []InputPath
+
+func InputPathListToPaths(list string) InputPaths {
+	var splited []string = filepath.SplitList(list)
+	var ret InputPaths = make(InputPaths, 0, len(splited))
+	for _, p := range splited {
+		if "" == p {
+			continue
+		}
+		ret = append(ret, InputPath(p))
+	}
+	return ret
+}
+
+func (p InputPaths) ToNames() NameIter {
+	return func(yield func(string, error) bool) {
+		for _, path := range p {
+			for name, e := range path.ToNames() {
+				if !yield(name, e) {
+					return
+				}
+				if nil != e {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (p InputPaths) ToSources() NameSources { return p.ToSourcesDelim(NameDelimNewline) }
+
+func (p InputPaths) ToSourcesDelim(d NameDelim) NameSources {
+	var ret NameSources = make(NameSources, 0, len(p))
+	for _, path := range p {
+		ret = append(ret, NameSource{
+			Tag:   string(path),
+			Names: path.ToNamesDelim(d),
+		})
+	}
+	return ret
+}
+
+func (n NameIter) Filter(keep func(string) bool) NameIter {
+	return func(yield func(string, error) bool) {
+		for name, e := range n {
+			if nil == e && !keep(name) {
+				continue
+			}
+			if !yield(name, e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package names2stats
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"time"
+)
+
+type StringToFileType func(string) FileType
+
+func (m FileTypeToStringMap) ToStringToFileType() StringToFileType {
+	var inverse map[string]FileType = make(map[string]FileType, len(m))
+	for typ, s := range m {
+		inverse[s] = typ
+	}
+	return func(s string) FileType {
+		return inverse[s]
+	}
+}
+
+var StringToFileTypeDefault StringToFileType = FileTypeToStringMapDefault.
+	ToStringToFileType()
+
+func (j BasicStatJson) ToBasicStat(s2t StringToFileType) BasicStat {
+	return BasicStat{
+		Path:     j.Path,
+		Size:     j.Size,
+		Modified: UnixtimeUs(j.Modified.UnixMicro()),
+		FileType: s2t(j.FileType),
+	}
+}
+
+func (s StringToFileType) RecordToBasicStat(rec Record) BasicStat {
+	var ret BasicStat
+
+	p, _ := rec.Get("path")
+	ret.Path, _ = p.(string)
+
+	size, _ := rec.Get("size")
+	ret.Size, _ = size.(int64)
+
+	modified, _ := rec.Get("modified_time")
+	t, ok := modified.(time.Time)
+	if ok {
+		ret.Modified = UnixtimeUs(t.UnixMicro())
+	}
+
+	typ, _ := rec.Get("file_type")
+	name, _ := typ.(string)
+	ret.FileType = s(name)
+
+	return ret
+}
+
+func (s StringToFileType) RecordsToBasicStats(
+	records RecordIter,
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for rec, e := range records {
+			if nil != e {
+				yield(BasicStat{}, e)
+				return
+			}
+			if !IsStatRecord(rec) {
+				continue
+			}
+			if !yield(s.RecordToBasicStat(rec), nil) {
+				return
+			}
+		}
+	}
+}
+
+const RecordTypeStat string = "stat"
+
+var ErrUnsupportedSchemaVersion error = errors.New("unsupported schema version")
+
+type recordTypeProbe struct {
+	RecordType    string `json:"record_type"`
+	SchemaVersion int    `json:"schema_version"`
+}
+
+type SchemaPolicy int
+
+const (
+	SchemaLenient SchemaPolicy = iota
+	SchemaStrict
+)
+
+func (p SchemaPolicy) Check(version int) error {
+	switch {
+	case version <= SchemaVersionCurrent:
+		return nil
+	case SchemaLenient == p:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d", ErrUnsupportedSchemaVersion, version)
+	}
+}
+
+func ReaderToRawRecords(rdr io.Reader) iter.Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		var dec *json.Decoder = json.NewDecoder(rdr)
+		for n := 1; ; n++ {
+			var raw json.RawMessage
+			e := dec.Decode(&raw)
+			if errors.Is(e, io.EOF) {
+				return
+			}
+			if nil != e {
+				yield(raw, fmt.Errorf("record %d: %w", n, e))
+				return
+			}
+			if !yield(raw, nil) {
+				return
+			}
+		}
+	}
+}
+
+func RawRecordType(raw json.RawMessage) (string, error) {
+	var probe recordTypeProbe
+	e := json.Unmarshal(raw, &probe)
+	switch probe.RecordType {
+	case "":
+		return RecordTypeStat, e
+	default:
+		return probe.RecordType, e
+	}
+}
+
+func (p SchemaPolicy) ReaderToBasicStats(
+	rdr io.Reader,
+) iter.Seq2[BasicStatJson, error] {
+	return func(yield func(BasicStatJson, error) bool) {
+		var n int
+		var declared int
+		for raw, e := range ReaderToRawRecords(rdr) {
+			n++
+
+			var j BasicStatJson
+			if nil != e {
+				yield(j, e)
+				return
+			}
+
+			var probe recordTypeProbe
+			e = json.Unmarshal(raw, &probe)
+
+			var version int = cmp.Or(probe.SchemaVersion, declared)
+			if nil == e {
+				e = p.Check(version)
+			}
+
+			switch {
+			case nil != e:
+			case RecordTypeHeader == probe.RecordType:
+				declared = probe.SchemaVersion
+				continue
+			case "" != probe.RecordType && RecordTypeStat != probe.RecordType:
+				continue
+			default:
+				e = json.Unmarshal(raw, &j)
+			}
+
+			if nil != e {
+				yield(j, fmt.Errorf("record %d: %w", n, e))
+				return
+			}
+
+			if !yield(j, nil) {
+				return
+			}
+		}
+	}
+}
+
+func ReaderToBasicStats(rdr io.Reader) iter.Seq2[BasicStatJson, error] {
+	return SchemaLenient.ReaderToBasicStats(rdr)
+}
+
+func (s StringToFileType) ReaderToBasicStats(
+	rdr io.Reader,
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for j, e := range ReaderToBasicStats(rdr) {
+			if !yield(j.ToBasicStat(s), e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package names2stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var ErrInvalidLabels error = errors.New("invalid labels")
+
+var reservedLabelKeys []string = []string{
+	"path",
+	"size",
+	"modified_time",
+	"file_type",
+	"record_type",
+}
+
+func ParseLabels(raw string) (Record, error) {
+	var dec *json.Decoder = json.NewDecoder(bytes.NewReader([]byte(raw)))
+	dec.UseNumber()
+
+	tok, e := dec.Token()
+	if nil != e {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLabels, e)
+	}
+	if json.Delim('{') != tok {
+		return nil, fmt.Errorf("%w: not an object", ErrInvalidLabels)
+	}
+
+	var ret Record
+	for dec.More() {
+		tok, e = dec.Token()
+		if nil != e {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidLabels, e)
+		}
+		var key string = tok.(string)
+
+		if slices.Contains(reservedLabelKeys, key) {
+			return nil, fmt.Errorf("%w: reserved key %q", ErrInvalidLabels, key)
+		}
+		_, dup := ret.Get(key)
+		if dup {
+			return nil, fmt.Errorf("%w: duplicate key %q", ErrInvalidLabels, key)
+		}
+
+		var val any
+		e = dec.Decode(&val)
+		if nil != e {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidLabels, key, e)
+		}
+		ret = append(ret, Field{Key: key, Value: val})
+	}
+
+	_, e = dec.Token()
+	if nil != e {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLabels, e)
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidLabels)
+	}
+	return ret, nil
+}
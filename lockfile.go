@@ -0,0 +1,66 @@
+package names2stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrLocked          error = errors.New("lock file held by another run")
+	ErrLockUnsupported error = errors.New("file locking unsupported")
+)
+
+var LockPollIntervalDefault time.Duration = 100 * time.Millisecond
+
+type FileLock struct{ file *os.File }
+
+func AcquireFileLock(
+	ctx context.Context,
+	name string,
+	wait time.Duration,
+) (*FileLock, error) {
+	f, e := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+	if nil != e {
+		return nil, e
+	}
+
+	var deadline time.Time = time.Now().Add(wait)
+	for {
+		locked, e := tryLockFile(f)
+		if nil != e {
+			return nil, errors.Join(e, f.Close())
+		}
+
+		if locked {
+			var pid string = strconv.Itoa(os.Getpid()) + "\n"
+			e = errors.Join(f.Truncate(0), writeAt0(f, pid))
+			if nil != e {
+				return nil, errors.Join(e, f.Close())
+			}
+			return &FileLock{file: f}, nil
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, errors.Join(fmt.Errorf("%w: %s", ErrLocked, name), f.Close())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(ctx.Err(), f.Close())
+		case <-time.After(LockPollIntervalDefault):
+		}
+	}
+}
+
+func writeAt0(f *os.File, s string) error {
+	_, e := f.WriteAt([]byte(s), 0)
+	return e
+}
+
+func (l *FileLock) Release() error {
+	return errors.Join(unlockFile(l.file), l.file.Close())
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package names2stats
+
+import (
+	"os"
+)
+
+func tryLockFile(_ *os.File) (bool, error) { return false, ErrLockUnsupported }
+
+func unlockFile(_ *os.File) error { return ErrLockUnsupported }
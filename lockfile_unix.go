@@ -0,0 +1,25 @@
+//go:build unix
+
+package names2stats
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func tryLockFile(f *os.File) (bool, error) {
+	e := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	switch {
+	case nil == e:
+		return true, nil
+	case errors.Is(e, syscall.EWOULDBLOCK):
+		return false, nil
+	default:
+		return false, e
+	}
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,5 @@
+//go:build !windows
+
+package names2stats
+
+func (d RootDirname) ToExtendedLength() RootDirname { return d }
@@ -0,0 +1,45 @@
+//go:build windows
+
+package names2stats
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	extendedPrefix    string = `\\?\`
+	extendedUncPrefix string = `\\?\UNC\`
+	devicePrefix      string = `\\.\`
+)
+
+func isUncVolume(vol string) bool {
+	server, share, found := strings.Cut(strings.TrimPrefix(vol, `\\`), `\`)
+	return strings.HasPrefix(vol, `\\`) &&
+		found &&
+		"" != server &&
+		"" != share &&
+		"." != server &&
+		"?" != server
+}
+
+func (d RootDirname) ToExtendedLength() RootDirname {
+	var s string = filepath.FromSlash(string(d))
+	if strings.HasPrefix(s, extendedPrefix) || strings.HasPrefix(s, devicePrefix) {
+		return d
+	}
+
+	abs, e := filepath.Abs(s)
+	if nil != e {
+		return d
+	}
+
+	switch {
+	case isUncVolume(filepath.VolumeName(abs)):
+		return RootDirname(extendedUncPrefix + abs[2:])
+	case strings.HasPrefix(abs, `\\`):
+		return d
+	default:
+		return RootDirname(extendedPrefix + abs)
+	}
+}
@@ -0,0 +1,146 @@
+package names2stats
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+)
+
+var lsTypeChars map[FileType]byte = map[FileType]byte{
+	FileTypeRglr: '-',
+	FileTypeFldr: 'd',
+	FileTypeSyml: 'l',
+	FileTypePipe: 'p',
+	FileTypeSock: 's',
+	FileTypeChar: 'c',
+	FileTypeBlck: 'b',
+}
+
+func lsTypeChar(t FileType) byte {
+	c, found := lsTypeChars[t]
+	switch found {
+	case true:
+		return c
+	default:
+		return '?'
+	}
+}
+
+func lsSpecial(buf []byte, i int, set bool, exec byte, noexec byte) {
+	if !set {
+		return
+	}
+	switch buf[i] {
+	case 'x':
+		buf[i] = exec
+	default:
+		buf[i] = noexec
+	}
+}
+
+func LsMode(t FileType, m fs.FileMode) string {
+	var buf []byte = []byte{lsTypeChar(t), '-', '-', '-', '-', '-', '-', '-', '-', '-'}
+	const rwx string = "rwxrwxrwx"
+	for i := range 9 {
+		if 0 != m&(1<<uint(8-i)) {
+			buf[1+i] = rwx[i]
+		}
+	}
+	lsSpecial(buf, 3, 0 != m&fs.ModeSetuid, 's', 'S')
+	lsSpecial(buf, 6, 0 != m&fs.ModeSetgid, 's', 'S')
+	lsSpecial(buf, 9, 0 != m&fs.ModeSticky, 't', 'T')
+	return string(buf)
+}
+
+var enricherMode Enricher = EnricherFunc(
+	func(_ context.Context, root Root, stat BasicStat) (EnrichedStat, error) {
+		fi, e := root.NameToInfo(stat.Path)
+		if nil != e {
+			return EnrichedStat{BasicStat: stat}, e
+		}
+		return EnrichedStat{
+			BasicStat: stat,
+			Extra:     map[string]any{"mode": LsMode(stat.FileType, fi.Mode())},
+		}, nil
+	},
+)
+
+var EnricherMode Enricher = DescribedEnricher{
+	Enricher: enricherMode,
+	Fields:   []FieldSchema{{Key: "mode", Types: []string{"string"}}},
+}
+
+type LsRenderer struct {
+	HumanSize bool
+	Now       func() time.Time
+}
+
+func (l LsRenderer) date(t time.Time) string {
+	var now time.Time = time.Now()
+	if nil != l.Now {
+		now = l.Now()
+	}
+
+	t = t.Local()
+	var age time.Duration = now.Sub(t)
+	if age < 0 || 182*24*time.Hour < age {
+		return t.Format("Jan _2  2006")
+	}
+	return t.Format("Jan _2 15:04")
+}
+
+func (l LsRenderer) Render(rec Record) string {
+	typ, _ := rec.Get("record_type")
+	if nil != typ && RecordTypeStat != typ {
+		return HumanRenderer{}.Render(rec)
+	}
+
+	var mode string = "??????????"
+	var size string
+	var modified string
+	var name string
+	for _, f := range rec {
+		switch f.Key {
+		case "mode":
+			mode = fmt.Sprint(f.Value)
+		case "file_type":
+			if "??????????" == mode {
+				var t FileType = StringToFileTypeDefault(fmt.Sprint(f.Value))
+				mode = string(lsTypeChar(t)) + mode[1:]
+			}
+		case "size":
+			n, _ := f.Value.(int64)
+			size = fmt.Sprintf("%d", n)
+			if l.HumanSize {
+				size = HumanSize(n)
+			}
+		case "modified_time":
+			t, _ := f.Value.(time.Time)
+			modified = l.date(t)
+		case "path":
+			name = fmt.Sprint(f.Value)
+		}
+	}
+	return fmt.Sprintf("%s %8s %s %s", mode, size, modified, name)
+}
+
+func (l LsRenderer) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			_, e = bw.WriteString(l.Render(rec) + "\n")
+			if nil != e {
+				return e
+			}
+		}
+		return bw.Flush()
+	}
+}
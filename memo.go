@@ -0,0 +1,10 @@
+package names2stats
+
+func (i FilenameToBasicStat) Memoized(capacity int) FilenameToBasicStat {
+	if capacity < 1 {
+		return i
+	}
+
+	var c *StatCache = &StatCache{MaxEntries: capacity}
+	return c.Wrap(i)
+}
@@ -0,0 +1,85 @@
+package names2stats
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+type MetricsSink interface {
+	CountCall(err error)
+	ObserveLatency(latency time.Duration)
+}
+
+func (i FilenameToBasicStat) WithMetrics(sink MetricsSink) FilenameToBasicStat {
+	return func(fullpath string) (BasicStat, error) {
+		var started time.Time = time.Now()
+		s, e := i(fullpath)
+		sink.ObserveLatency(time.Since(started))
+		sink.CountCall(e)
+		return s, e
+	}
+}
+
+var LatencyBoundsDefault []time.Duration = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+type MetricsSnapshot struct {
+	Calls   uint64          `json:"calls"`
+	Errors  uint64          `json:"errors"`
+	Bounds  []time.Duration `json:"bounds_ns"`
+	Buckets []uint64        `json:"buckets"`
+	Sum     time.Duration   `json:"sum_ns"`
+}
+
+type MemoryMetrics struct {
+	bounds  []time.Duration
+	buckets []atomic.Uint64
+	calls   atomic.Uint64
+	errors  atomic.Uint64
+	sum     atomic.Int64
+}
+
+func NewMemoryMetrics(bounds []time.Duration) *MemoryMetrics {
+	var sorted []time.Duration = append([]time.Duration(nil), bounds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return &MemoryMetrics{
+		bounds:  sorted,
+		buckets: make([]atomic.Uint64, len(sorted)+1),
+	}
+}
+
+func (m *MemoryMetrics) CountCall(err error) {
+	m.calls.Add(1)
+	if nil != err {
+		m.errors.Add(1)
+	}
+}
+
+func (m *MemoryMetrics) ObserveLatency(latency time.Duration) {
+	var idx int = sort.Search(len(m.bounds), func(i int) bool {
+		return latency <= m.bounds[i]
+	})
+	m.buckets[idx].Add(1)
+	m.sum.Add(int64(latency))
+}
+
+func (m *MemoryMetrics) Snapshot() MetricsSnapshot {
+	var buckets []uint64 = make([]uint64, len(m.buckets))
+	for i := range m.buckets {
+		buckets[i] = m.buckets[i].Load()
+	}
+	return MetricsSnapshot{
+		Calls:   m.calls.Load(),
+		Errors:  m.errors.Load(),
+		Bounds:  m.bounds,
+		Buckets: buckets,
+		Sum:     time.Duration(m.sum.Load()),
+	}
+}
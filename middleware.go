@@ -0,0 +1,84 @@
+package names2stats
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrStatTimeout error = errors.New("stat timed out")
+
+type Middleware func(FilenameToBasicStat) FilenameToBasicStat
+
+func Chain(layers ...Middleware) Middleware {
+	return func(inner FilenameToBasicStat) FilenameToBasicStat {
+		var wrapped FilenameToBasicStat = inner
+		for i := len(layers) - 1; 0 <= i; i-- {
+			wrapped = layers[i](wrapped)
+		}
+		return wrapped
+	}
+}
+
+func (i FilenameToBasicStat) With(layers ...Middleware) FilenameToBasicStat {
+	return Chain(layers...)(i)
+}
+
+func MemoizedMiddleware(capacity int) Middleware {
+	return func(i FilenameToBasicStat) FilenameToBasicStat {
+		return i.Memoized(capacity)
+	}
+}
+
+func MetricsMiddleware(sink MetricsSink) Middleware {
+	return func(i FilenameToBasicStat) FilenameToBasicStat {
+		return i.WithMetrics(sink)
+	}
+}
+
+func (c *StatCache) ToMiddleware() Middleware { return c.Wrap }
+
+func RetryMiddleware(
+	attempts int,
+	delay time.Duration,
+	retryable func(error) bool,
+) Middleware {
+	if nil == retryable {
+		retryable = func(error) bool { return true }
+	}
+	return func(i FilenameToBasicStat) FilenameToBasicStat {
+		return func(fullpath string) (BasicStat, error) {
+			s, e := i(fullpath)
+			for n := 1; n < attempts && nil != e && retryable(e); n++ {
+				time.Sleep(delay)
+				s, e = i(fullpath)
+			}
+			return s, e
+		}
+	}
+}
+
+func TimeoutMiddleware(d time.Duration) Middleware {
+	return func(i FilenameToBasicStat) FilenameToBasicStat {
+		if d <= 0 {
+			return i
+		}
+		return func(fullpath string) (BasicStat, error) {
+			var done chan statResult = make(chan statResult, 1)
+			go func() {
+				s, e := i(fullpath)
+				done <- statResult{stat: s, err: e}
+			}()
+
+			var timer *time.Timer = time.NewTimer(d)
+			defer timer.Stop()
+			select {
+			case r := <-done:
+				return r.stat, r.err
+			case <-timer.C:
+				var empty BasicStat
+				return empty, fmt.Errorf("%w: %s after %v", ErrStatTimeout, fullpath, d)
+			}
+		}
+	}
+}
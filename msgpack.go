@@ -0,0 +1,283 @@
+package names2stats
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+	"time"
+)
+
+var ErrMsgpackUnsupported error = errors.New("unsupported type for msgpack")
+
+const (
+	msgpackNil     byte = 0xc0
+	msgpackFalse   byte = 0xc2
+	msgpackTrue    byte = 0xc3
+	msgpackBin8    byte = 0xc4
+	msgpackBin16   byte = 0xc5
+	msgpackBin32   byte = 0xc6
+	msgpackFloat64 byte = 0xcb
+	msgpackUint8   byte = 0xcc
+	msgpackUint16  byte = 0xcd
+	msgpackUint32  byte = 0xce
+	msgpackUint64  byte = 0xcf
+	msgpackInt8    byte = 0xd0
+	msgpackInt16   byte = 0xd1
+	msgpackInt32   byte = 0xd2
+	msgpackInt64   byte = 0xd3
+	msgpackFixext8 byte = 0xd7
+	msgpackStr8    byte = 0xd9
+	msgpackStr16   byte = 0xda
+	msgpackStr32   byte = 0xdb
+	msgpackArray16 byte = 0xdc
+	msgpackArray32 byte = 0xdd
+	msgpackMap16   byte = 0xde
+	msgpackMap32   byte = 0xdf
+)
+
+const msgpackExtEventTime byte = 0
+
+func appendMsgpackUint(buf []byte, u uint64) []byte {
+	switch {
+	case u < 0x80:
+		return append(buf, byte(u))
+	case u <= math.MaxUint8:
+		return append(buf, msgpackUint8, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, msgpackUint16), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, msgpackUint32), uint32(u))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, msgpackUint64), u)
+	}
+}
+
+func appendMsgpackInt(buf []byte, i int64) []byte {
+	switch {
+	case 0 <= i:
+		return appendMsgpackUint(buf, uint64(i))
+	case -32 <= i:
+		return append(buf, byte(i))
+	case math.MinInt8 <= i:
+		return append(buf, msgpackInt8, byte(i))
+	case math.MinInt16 <= i:
+		return binary.BigEndian.AppendUint16(append(buf, msgpackInt16), uint16(i))
+	case math.MinInt32 <= i:
+		return binary.BigEndian.AppendUint32(append(buf, msgpackInt32), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, msgpackInt64), uint64(i))
+	}
+}
+
+func appendMsgpackFloat(buf []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(buf, msgpackFloat64), math.Float64bits(f))
+}
+
+func appendMsgpackHead(buf []byte, fix byte, m16 byte, m32 byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, m16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, m32), uint32(n))
+	}
+}
+
+func appendMsgpackStr(buf []byte, s string) []byte {
+	var n int = len(s)
+	switch {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, msgpackStr8, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, msgpackStr16), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, msgpackStr32), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+func appendMsgpackBin(buf []byte, b []byte) []byte {
+	var n int = len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf = append(buf, msgpackBin8, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, msgpackBin16), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, msgpackBin32), uint32(n))
+	}
+	return append(buf, b...)
+}
+
+func appendMsgpackMapHead(buf []byte, n int) []byte {
+	return appendMsgpackHead(buf, 0x80, msgpackMap16, msgpackMap32, n)
+}
+
+func appendMsgpackArrayHead(buf []byte, n int) []byte {
+	return appendMsgpackHead(buf, 0x90, msgpackArray16, msgpackArray32, n)
+}
+
+func AppendMsgpackEventTime(buf []byte, t time.Time) []byte {
+	buf = append(buf, msgpackFixext8, msgpackExtEventTime)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(buf, uint32(t.Nanosecond()))
+}
+
+func AppendMsgpack(buf []byte, val any) ([]byte, error) {
+	switch v := val.(type) {
+	case nil:
+		return append(buf, msgpackNil), nil
+	case bool:
+		if v {
+			return append(buf, msgpackTrue), nil
+		}
+		return append(buf, msgpackFalse), nil
+	case string:
+		return appendMsgpackStr(buf, v), nil
+	case []byte:
+		return appendMsgpackBin(buf, v), nil
+	case int:
+		return appendMsgpackInt(buf, int64(v)), nil
+	case int32:
+		return appendMsgpackInt(buf, int64(v)), nil
+	case int64:
+		return appendMsgpackInt(buf, v), nil
+	case uint32:
+		return appendMsgpackUint(buf, uint64(v)), nil
+	case uint64:
+		return appendMsgpackUint(buf, v), nil
+	case float32:
+		return appendMsgpackFloat(buf, float64(v)), nil
+	case float64:
+		return appendMsgpackFloat(buf, v), nil
+	case json.Number:
+		i, e := v.Int64()
+		if nil == e {
+			return appendMsgpackInt(buf, i), nil
+		}
+		f, e := v.Float64()
+		return appendMsgpackFloat(buf, f), e
+	case time.Time:
+		return appendMsgpackStr(buf, v.Format(time.RFC3339Nano)), nil
+	case Record:
+		buf = appendMsgpackMapHead(buf, len(v))
+		for _, f := range v {
+			var e error
+			buf, e = AppendMsgpack(appendMsgpackStr(buf, f.Key), f.Value)
+			if nil != e {
+				return buf, e
+			}
+		}
+		return buf, nil
+	case map[string]any:
+		return appendMsgpackMap(buf, v)
+	case map[string]int64:
+		return appendMsgpackMap(buf, v)
+	case []string:
+		return appendMsgpackArray(buf, v)
+	case []any:
+		return appendMsgpackArray(buf, v)
+	default:
+		return buf, fmt.Errorf("%w: %T", ErrMsgpackUnsupported, val)
+	}
+}
+
+func appendMsgpackMap[V any](buf []byte, m map[string]V) ([]byte, error) {
+	buf = appendMsgpackMapHead(buf, len(m))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		var e error
+		buf, e = AppendMsgpack(appendMsgpackStr(buf, key), m[key])
+		if nil != e {
+			return buf, e
+		}
+	}
+	return buf, nil
+}
+
+func appendMsgpackArray[V any](buf []byte, items []V) ([]byte, error) {
+	buf = appendMsgpackArrayHead(buf, len(items))
+	for _, item := range items {
+		var e error
+		buf, e = AppendMsgpack(buf, item)
+		if nil != e {
+			return buf, e
+		}
+	}
+	return buf, nil
+}
+
+type MsgpackOptions struct {
+	ForwardTag string
+	Now        func() time.Time
+}
+
+func (o MsgpackOptions) Append(buf []byte, rec Record) ([]byte, error) {
+	if "" == o.ForwardTag {
+		return AppendMsgpack(buf, rec)
+	}
+
+	var t time.Time = o.Now()
+	scanned, found := rec.Get("scanned_at")
+	if found {
+		st, ok := scanned.(time.Time)
+		if ok {
+			t = st
+		}
+	}
+
+	buf = appendMsgpackArrayHead(buf, 3)
+	buf = appendMsgpackStr(buf, o.ForwardTag)
+	buf = AppendMsgpackEventTime(buf, t)
+	return AppendMsgpack(buf, rec)
+}
+
+func (o MsgpackOptions) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	if nil == o.Now {
+		o.Now = time.Now
+	}
+
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		var buf []byte
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			buf, e = o.Append(buf[:0], rec)
+			if nil != e {
+				return e
+			}
+
+			_, e = bw.Write(buf)
+			if nil != e {
+				return e
+			}
+		}
+		return bw.Flush()
+	}
+}
+
+func RecordsToMsgpackWriter(wtr io.Writer) func(RecordIter) error {
+	return MsgpackOptions{}.RecordsToWriter(wtr)
+}
+
+func (c FileTypeToString) BasicStatsToMsgpackWriter(
+	wtr io.Writer,
+) func(iter.Seq2[BasicStat, error]) error {
+	return func(stats iter.Seq2[BasicStat, error]) error {
+		return RecordsToMsgpackWriter(wtr)(c.EnrichedStatsToRecords(
+			BasicStatsToEnriched(stats),
+		))
+	}
+}
@@ -3,11 +3,13 @@ package names2stats
 import (
 	"bufio"
 	"encoding/asn1"
-	"encoding/json"
+	"encoding/csv"
+	"errors"
 	"io"
 	"io/fs"
 	"iter"
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -157,6 +159,12 @@ type Root struct{ *os.Root }
 
 func (r Root) Close() error { return r.Root.Close() }
 
+func (r Root) ToFS() fs.FS { return r.Root.FS() }
+
+func (r Root) ReadDirInodes(dir string) ([]fs.DirEntry, error) {
+	return readDirInodes(r.Root, dir)
+}
+
 func (r Root) NameToInfo(fullpath string) (fs.FileInfo, error) {
 	return r.Root.Stat(fullpath)
 }
@@ -182,12 +190,50 @@ func (r Root) NamesToBasicStatsToStdout(
 	return r.ToFilenameToBasicStat().NamesToBasicStatsToStdout(names)
 }
 
+func (r Root) NameIterToBasicStatsToStdout(names NameIter) error {
+	return r.ToFilenameToBasicStat().NameIterToBasicStatsToStdout(names)
+}
+
+func (r Root) ExpandGlobs(patterns NameIter) NameIter {
+	return GlobsToNames(r.ToFS(), patterns)
+}
+
+func (r Root) ExpandTaggedGlobs(patterns TaggedNameIter) TaggedNameIter {
+	return r.ExpandTaggedGlobsCase(patterns, PathCaseSensitive)
+}
+
+func (r Root) ExpandTaggedGlobsCase(
+	patterns TaggedNameIter,
+	c PathCase,
+) TaggedNameIter {
+	var fsys fs.FS = r.ToFS()
+	return patterns.FlatMap(func(pat string) NameIter {
+		return GlobPattern(pat).ExpandCase(fsys, c)
+	})
+}
+
+func (r Root) TaggedToBasicStatsToStdout(names TaggedNameIter) error {
+	var stats iter.Seq2[BasicStat, error] = r.
+		ToFilenameToBasicStat().
+		TaggedToBasicStats(names)
+	return BasicStatsToStdoutDefault(stats)
+}
+
 type RootDirname string
 
 func (d RootDirname) ToRoot() (*os.Root, error) {
 	return os.OpenRoot(string(d))
 }
 
+func (d RootDirname) WithRoot(f func(Root) error) error {
+	rt, e := d.ToRoot()
+	if nil != e {
+		return e
+	}
+	defer rt.Close()
+	return f(Root{rt})
+}
+
 func (d RootDirname) NamesToBasicStatsToStdout(
 	names iter.Seq[string],
 ) error {
@@ -199,6 +245,12 @@ func (d RootDirname) NamesToBasicStatsToStdout(
 	return Root{rt}.NamesToBasicStatsToStdout(names)
 }
 
+func (d RootDirname) NameIterToBasicStatsToStdout(names NameIter) error {
+	return d.WithRoot(func(r Root) error {
+		return r.NameIterToBasicStatsToStdout(names)
+	})
+}
+
 func (i FilenameToBasicStat) NamesToBasicStats(
 	names iter.Seq[string],
 ) iter.Seq2[BasicStat, error] {
@@ -212,6 +264,25 @@ func (i FilenameToBasicStat) NamesToBasicStats(
 	}
 }
 
+func (i FilenameToBasicStat) NameIterToBasicStats(
+	names NameIter,
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		var empty BasicStat
+		for name, e := range names {
+			if nil != e {
+				yield(empty, e)
+				return
+			}
+
+			s, e := i(name)
+			if !yield(s, e) {
+				return
+			}
+		}
+	}
+}
+
 func (i FilenameToBasicStat) NamesToBasicStatsToStdout(
 	names iter.Seq[string],
 ) error {
@@ -219,6 +290,13 @@ func (i FilenameToBasicStat) NamesToBasicStatsToStdout(
 	return BasicStatsToStdoutDefault(stats)
 }
 
+func (i FilenameToBasicStat) NameIterToBasicStatsToStdout(
+	names NameIter,
+) error {
+	var stats iter.Seq2[BasicStat, error] = i.NameIterToBasicStats(names)
+	return BasicStatsToStdoutDefault(stats)
+}
+
 type BasicStatIter iter.Seq2[BasicStat, error]
 
 func (i BasicStatIter) Collect() ([]BasicStat, error) {
@@ -239,14 +317,16 @@ func (c FileTypeToString) BasicStatsToWriter(
 		var bw *bufio.Writer = bufio.NewWriter(wtr)
 		defer bw.Flush()
 
-		var enc *json.Encoder = json.NewEncoder(bw)
+		var line []byte
 		for s, e := range stats {
 			if nil != e {
 				return e
 			}
 
-			var j BasicStatJson = s.ToJsonObj(c)
-			e := enc.Encode(j)
+			line, e = c.AppendBasicStatJSON(line[:0], s)
+			if nil == e {
+				_, e = bw.Write(append(line, '\n'))
+			}
 			if nil != e {
 				return e
 			}
@@ -256,6 +336,48 @@ func (c FileTypeToString) BasicStatsToWriter(
 	}
 }
 
+var BasicStatCsvHeader []string = []string{
+	"path",
+	"size",
+	"modified_time",
+	"file_type",
+}
+
+func (c FileTypeToString) BasicStatsToCsvWriter(
+	wtr io.Writer,
+) func(iter.Seq2[BasicStat, error]) error {
+	return func(stats iter.Seq2[BasicStat, error]) error {
+		var cw *csv.Writer = csv.NewWriter(wtr)
+		cw.UseCRLF = true
+
+		e := cw.Write(BasicStatCsvHeader)
+		if nil != e {
+			return e
+		}
+
+		for s, e := range stats {
+			if nil != e {
+				cw.Flush()
+				return errors.Join(e, cw.Error())
+			}
+
+			var j BasicStatJson = s.ToJsonObj(c)
+			e = cw.Write([]string{
+				j.Path,
+				strconv.FormatInt(j.Size, 10),
+				j.Modified.Format(time.RFC3339Nano),
+				j.FileType,
+			})
+			if nil != e {
+				return e
+			}
+		}
+
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
 func (c FileTypeToString) BasicStatsToStdout(
 	stats iter.Seq2[BasicStat, error],
 ) error {
@@ -272,12 +394,14 @@ func (b BasicStats) ToAsn1DerBytes() ([]byte, error) {
 	return asn1.Marshal(b)
 }
 
+func ReaderToNameIter(rdr io.Reader) NameIter {
+	return NameDelimNewline.ReaderToNameIter(rdr)
+}
+
 func ReaderToNames(rdr io.Reader) iter.Seq[string] {
 	return func(yield func(string) bool) {
-		var s *bufio.Scanner = bufio.NewScanner(rdr)
-		for s.Scan() {
-			var fullpath string = s.Text()
-			if !yield(fullpath) {
+		for name, e := range ReaderToNameIter(rdr) {
+			if nil != e || !yield(name) {
 				return
 			}
 		}
@@ -285,3 +409,5 @@ func ReaderToNames(rdr io.Reader) iter.Seq[string] {
 }
 
 func StdinToNames() iter.Seq[string] { return ReaderToNames(os.Stdin) }
+
+func StdinToNameIter() NameIter { return ReaderToNameIter(os.Stdin) }
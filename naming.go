@@ -0,0 +1,148 @@
+package names2stats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var (
+	ErrUnknownKeyCase  error = errors.New("unknown key case")
+	ErrInvalidRenaming error = errors.New("invalid key renaming")
+)
+
+type KeyCase int
+
+const (
+	KeyCaseAsIs KeyCase = iota
+	KeyCaseSnake
+	KeyCaseCamel
+	KeyCaseKebab
+)
+
+func ParseKeyCase(s string) (KeyCase, error) {
+	switch s {
+	case "asis":
+		return KeyCaseAsIs, nil
+	case "snake", "snake_case":
+		return KeyCaseSnake, nil
+	case "camel", "camelCase":
+		return KeyCaseCamel, nil
+	case "kebab", "kebab-case":
+		return KeyCaseKebab, nil
+	default:
+		return KeyCaseAsIs, fmt.Errorf("%w: %s", ErrUnknownKeyCase, s)
+	}
+}
+
+func KeyToWords(key string) []string {
+	var words []string
+	var cur []rune
+	var prevLower bool
+
+	var flush func() = func() {
+		if 0 < len(cur) {
+			words = append(words, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+
+	for _, r := range key {
+		switch {
+		case '_' == r || '-' == r:
+			flush()
+			prevLower = false
+		case unicode.IsUpper(r) && prevLower:
+			flush()
+			cur = append(cur, r)
+			prevLower = false
+		default:
+			cur = append(cur, r)
+			prevLower = unicode.IsLower(r) || unicode.IsDigit(r)
+		}
+	}
+	flush()
+	return words
+}
+
+func (k KeyCase) Convert(key string) string {
+	var words []string = KeyToWords(key)
+	switch k {
+	case KeyCaseCamel:
+		for i := 1; i < len(words); i++ {
+			var w []rune = []rune(words[i])
+			w[0] = unicode.ToUpper(w[0])
+			words[i] = string(w)
+		}
+		return strings.Join(words, "")
+	case KeyCaseKebab:
+		return strings.Join(words, "-")
+	default:
+		return strings.Join(words, "_")
+	}
+}
+
+func (k KeyCase) Apply(rec Record) Record {
+	if KeyCaseAsIs == k {
+		return rec
+	}
+
+	var ret Record = make(Record, 0, len(rec))
+	for _, f := range rec {
+		ret = append(ret, Field{Key: k.Convert(f.Key), Value: f.Value})
+	}
+	return ret
+}
+
+type KeyRenames map[string]string
+
+func ParseKeyRenames(s string) (KeyRenames, error) {
+	var ret KeyRenames = KeyRenames{}
+	if strings.HasPrefix(strings.TrimSpace(s), "{") {
+		e := json.Unmarshal([]byte(s), &ret)
+		if nil != e {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRenaming, e)
+		}
+		return ret, nil
+	}
+
+	for pair := range strings.SplitSeq(s, ",") {
+		if "" == strings.TrimSpace(pair) {
+			continue
+		}
+		from, to, found := strings.Cut(pair, "=")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !found || "" == from || "" == to {
+			return nil, fmt.Errorf("%w: %q: want old=new", ErrInvalidRenaming, pair)
+		}
+		ret[from] = to
+	}
+	return ret, nil
+}
+
+func (r KeyRenames) Convert(k KeyCase, key string) string {
+	renamed, found := r[key]
+	switch {
+	case found:
+		return renamed
+	case KeyCaseAsIs == k:
+		return key
+	default:
+		return k.Convert(key)
+	}
+}
+
+func (r KeyRenames) Apply(k KeyCase) RecordMapper {
+	if 0 == len(r) {
+		return k.Apply
+	}
+	return func(rec Record) Record {
+		var ret Record = make(Record, 0, len(rec))
+		for _, f := range rec {
+			ret = append(ret, Field{Key: r.Convert(k, f.Key), Value: f.Value})
+		}
+		return ret
+	}
+}
@@ -0,0 +1,130 @@
+package names2stats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+)
+
+const (
+	NcduMajorVersion int    = 1
+	NcduMinorVersion int    = 0
+	NcduProgname     string = "names2stats"
+)
+
+type NcduOptions struct {
+	Root string
+	Now  func() time.Time
+}
+
+type ncduInfo struct {
+	Name   string `json:"name"`
+	Asize  int64  `json:"asize,omitempty"`
+	Dsize  int64  `json:"dsize,omitempty"`
+	Notreg bool   `json:"notreg,omitempty"`
+	Mtime  int64  `json:"mtime,omitempty"`
+}
+
+type ncduMeta struct {
+	Progname  string `json:"progname"`
+	Progver   string `json:"progver"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func (o NcduOptions) info(n *DirNode) ncduInfo {
+	var info ncduInfo = ncduInfo{Name: n.Name}
+	if nil == n.Parent && "" != o.Root {
+		info.Name = o.Root
+	}
+	if !n.Seen {
+		return info
+	}
+
+	switch n.Stat.FileType {
+	case FileTypeRglr, FileTypeFldr:
+	default:
+		info.Notreg = !n.IsDir()
+	}
+	info.Asize = n.Stat.Size
+	info.Dsize = n.Stat.Size
+	info.Mtime = n.Stat.Modified.ToTime().Unix()
+	return info
+}
+
+func (o NcduOptions) writeNode(bw *bufio.Writer, n *DirNode) error {
+	raw, e := json.Marshal(o.info(n))
+	if nil != e {
+		return e
+	}
+	if !n.IsDir() {
+		_, e = bw.Write(raw)
+		return e
+	}
+
+	_, e = bw.WriteString("[")
+	if nil == e {
+		_, e = bw.Write(raw)
+	}
+	for _, name := range slices.Sorted(maps.Keys(n.Children)) {
+		if nil != e {
+			return e
+		}
+		_, e = bw.WriteString(",\n")
+		if nil == e {
+			e = o.writeNode(bw, n.Children[name])
+		}
+	}
+	if nil != e {
+		return e
+	}
+	_, e = bw.WriteString("]")
+	return e
+}
+
+func (o NcduOptions) Write(wtr io.Writer, tree *DirNode) error {
+	var now time.Time = time.Now()
+	if nil != o.Now {
+		now = o.Now()
+	}
+
+	meta, e := json.Marshal(ncduMeta{
+		Progname:  NcduProgname,
+		Progver:   ToolVersion(),
+		Timestamp: now.Unix(),
+	})
+	if nil != e {
+		return e
+	}
+
+	var bw *bufio.Writer = bufio.NewWriter(wtr)
+	_, e = fmt.Fprintf(bw, "[%d,%d,%s,\n", NcduMajorVersion, NcduMinorVersion, meta)
+	if nil == e {
+		e = o.writeNode(bw, tree)
+	}
+	if nil == e {
+		_, e = bw.WriteString("]\n")
+	}
+	if nil != e {
+		return e
+	}
+	return bw.Flush()
+}
+
+func (o NcduOptions) Sink() SinkFactory {
+	return func(_ context.Context, wtr io.Writer) (Sink, error) {
+		var tree *DirNode = NewDirTree()
+		return StatSink{
+			Stat: func(s BasicStat) error {
+				tree.Insert(s)
+				return nil
+			},
+			Done: func() error { return o.Write(wtr, tree) },
+			Fail: func(error) error { return nil },
+		}, nil
+	}
+}
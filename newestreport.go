@@ -0,0 +1,47 @@
+package names2stats
+
+import (
+	"iter"
+)
+
+const RecordTypeDirMtime string = "dir_mtime"
+
+type NewestMtimeReport struct {
+	Tree *DirNode
+}
+
+func NewNewestMtimeReport() *NewestMtimeReport {
+	return &NewestMtimeReport{Tree: NewDirTree()}
+}
+
+func (rep *NewestMtimeReport) Filter(
+	_ Root,
+	stats iter.Seq2[BasicStat, error],
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for s, e := range stats {
+			if nil != e {
+				yield(s, e)
+				return
+			}
+			rep.Tree.Insert(s)
+		}
+	}
+}
+
+func (rep *NewestMtimeReport) Summary() []Record {
+	var ret []Record
+	for node := range rep.Tree.All() {
+		if !node.IsDir() || "" == node.Newest.Path {
+			continue
+		}
+		ret = append(ret, Record{
+			{Key: "record_type", Value: RecordTypeDirMtime},
+			{Key: "dir", Value: node.Path()},
+			{Key: "newest_mtime", Value: node.Newest.Modified.ToTime()},
+			{Key: "newest_path", Value: node.Newest.Path},
+			{Key: "entries", Value: node.Entries},
+		})
+	}
+	return ret
+}
@@ -0,0 +1,154 @@
+package names2stats
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const MetricsPrefixDefault string = "names2stats"
+
+type MetricsKey struct {
+	FileType  string
+	Extension string
+}
+
+type MetricsSummary struct {
+	Bytes map[MetricsKey]int64
+	Files map[MetricsKey]int64
+}
+
+func NewMetricsSummary() *MetricsSummary {
+	return &MetricsSummary{
+		Bytes: map[MetricsKey]int64{},
+		Files: map[MetricsKey]int64{},
+	}
+}
+
+func (s *MetricsSummary) Observe(rec Record) {
+	pathVal, _ := rec.Get("path")
+	fileType, _ := rec.Get("file_type")
+	size, _ := rec.Get("size")
+
+	var ext string = path.Ext(CsvValue(pathVal))
+	if "." == ext {
+		ext = ""
+	}
+
+	var key MetricsKey = MetricsKey{
+		FileType:  CsvValue(fileType),
+		Extension: strings.ToLower(ext),
+	}
+	s.Files[key]++
+
+	n, ok := size.(int64)
+	if ok {
+		s.Bytes[key] += n
+	}
+}
+
+var metricsLabelEscaper *strings.Replacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"\"", "\\\"",
+	"\n", "\\n",
+)
+
+type MetricsOptions struct {
+	Prefix string
+	Labels Record
+	Now    func() time.Time
+}
+
+func (o MetricsOptions) labels(key *MetricsKey) string {
+	var pairs []string
+	for _, f := range o.Labels {
+		pairs = append(pairs, fmt.Sprintf(
+			"%s=\"%s\"",
+			f.Key,
+			metricsLabelEscaper.Replace(CsvValue(f.Value)),
+		))
+	}
+	if nil != key {
+		pairs = append(
+			pairs,
+			"type=\""+metricsLabelEscaper.Replace(key.FileType)+"\"",
+			"extension=\""+metricsLabelEscaper.Replace(key.Extension)+"\"",
+		)
+	}
+	if 0 == len(pairs) {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func compareMetricsKey(a, b MetricsKey) int {
+	return cmp.Or(
+		strings.Compare(a.FileType, b.FileType),
+		strings.Compare(a.Extension, b.Extension),
+	)
+}
+
+func (o MetricsOptions) writeFamily(
+	bw *bufio.Writer,
+	name string,
+	help string,
+	values map[MetricsKey]int64,
+) {
+	var full string = o.Prefix + "_" + name
+	_, _ = fmt.Fprintf(bw, "# TYPE %s gauge\n", full)
+	_, _ = fmt.Fprintf(bw, "# HELP %s %s\n", full, help)
+	var keys []MetricsKey = slices.SortedFunc(maps.Keys(values), compareMetricsKey)
+	for _, key := range keys {
+		_, _ = fmt.Fprintf(bw, "%s%s %d\n", full, o.labels(&key), values[key])
+	}
+}
+
+func (o MetricsOptions) WriteSummary(wtr io.Writer, s *MetricsSummary) error {
+	var bw *bufio.Writer = bufio.NewWriter(wtr)
+
+	o.writeFamily(bw, "total_bytes", "Cumulative size of the scanned files.", s.Bytes)
+	o.writeFamily(bw, "file_count", "Number of the scanned files.", s.Files)
+
+	var ts string = o.Prefix + "_scan_timestamp_seconds"
+	_, _ = fmt.Fprintf(bw, "# TYPE %s gauge\n", ts)
+	_, _ = fmt.Fprintf(bw, "# HELP %s When the scan finished.\n", ts)
+	_, _ = fmt.Fprintf(
+		bw,
+		"%s%s %s\n",
+		ts,
+		o.labels(nil),
+		strconv.FormatFloat(float64(o.Now().UnixMilli())/1e3, 'f', -1, 64),
+	)
+
+	_, _ = bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+func (o MetricsOptions) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	if "" == o.Prefix {
+		o.Prefix = MetricsPrefixDefault
+	}
+	if nil == o.Now {
+		o.Now = time.Now
+	}
+
+	return func(records RecordIter) error {
+		var s *MetricsSummary = NewMetricsSummary()
+		for rec, e := range records {
+			if nil != e {
+				return e
+			}
+			if IsStatRecord(rec) {
+				s.Observe(rec)
+			}
+		}
+		return o.WriteSummary(wtr, s)
+	}
+}
@@ -0,0 +1,153 @@
+package names2stats
+
+import (
+	"iter"
+	"strconv"
+)
+
+type Origin struct {
+	Source string
+	Line   int
+}
+
+func (o Origin) String() string {
+	switch o.Line {
+	case 0:
+		return o.Source
+	default:
+		return o.Source + ":" + strconv.Itoa(o.Line)
+	}
+}
+
+type OriginError struct {
+	Origin
+	Err error
+}
+
+func (e OriginError) Error() string { return e.Origin.String() + ": " + e.Err.Error() }
+
+func (e OriginError) Unwrap() error { return e.Err }
+
+func (o Origin) WrapErr(e error) error {
+	switch e {
+	case nil:
+		return nil
+	default:
+		return OriginError{Origin: o, Err: e}
+	}
+}
+
+type TaggedName struct {
+	Name string
+	Origin
+}
+
+type TaggedNameIter iter.Seq2[TaggedName, error]
+
+type NameSource struct {
+	Tag   string
+	Names NameIter
+}
+
+func (s NameSource) ToTagged() TaggedNameIter {
+	return func(yield func(TaggedName, error) bool) {
+		var line int
+		for name, e := range s.Names {
+			line += 1
+			var o Origin = Origin{Source: s.Tag, Line: line}
+			if nil != e {
+				yield(TaggedName{Origin: o}, o.WrapErr(e))
+				return
+			}
+			if !yield(TaggedName{Name: name, Origin: o}, nil) {
+				return
+			}
+		}
+	}
+}
+
+type NameSources []NameSource
+
+func (s NameSources) ToTagged() TaggedNameIter {
+	return func(yield func(TaggedName, error) bool) {
+		for _, src := range s {
+			for tagged, e := range src.ToTagged() {
+				if !yield(tagged, e) {
+					return
+				}
+				if nil != e {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (t TaggedNameIter) ToNameIter() NameIter {
+	return func(yield func(string, error) bool) {
+		for tagged, e := range t {
+			if !yield(tagged.Name, e) {
+				return
+			}
+			if nil != e {
+				return
+			}
+		}
+	}
+}
+
+func (t TaggedNameIter) FlatMap(f func(string) NameIter) TaggedNameIter {
+	return func(yield func(TaggedName, error) bool) {
+		for tagged, e := range t {
+			if nil != e {
+				yield(tagged, e)
+				return
+			}
+
+			for name, e := range f(tagged.Name) {
+				var mapped TaggedName = TaggedName{
+					Name:   name,
+					Origin: tagged.Origin,
+				}
+				if !yield(mapped, tagged.Origin.WrapErr(e)) {
+					return
+				}
+				if nil != e {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (i FilenameToBasicStat) TaggedToBasicStats(
+	names TaggedNameIter,
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		var empty BasicStat
+		for tagged, e := range names {
+			if nil != e {
+				yield(empty, e)
+				return
+			}
+
+			s, e := i(tagged.Name)
+			if !yield(s, tagged.Origin.WrapErr(e)) {
+				return
+			}
+		}
+	}
+}
+
+func (t TaggedNameIter) Filter(keep func(string) bool) TaggedNameIter {
+	return func(yield func(TaggedName, error) bool) {
+		for tagged, e := range t {
+			if nil == e && !keep(tagged.Name) {
+				continue
+			}
+			if !yield(tagged, e) {
+				return
+			}
+		}
+	}
+}
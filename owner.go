@@ -0,0 +1,103 @@
+package names2stats
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNotOwned     error = errors.New("file not owned by the selected users/groups")
+	ErrOwnerUnknown error = errors.New("file owner unavailable")
+)
+
+type Owner struct {
+	Uid uint32
+	Gid uint32
+}
+
+type IDSet map[uint32]struct{}
+
+func parseIDs(list string, lookup func(string) (string, error)) (IDSet, error) {
+	var ret IDSet = IDSet{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if "" == item {
+			continue
+		}
+
+		id, e := strconv.ParseUint(item, 10, 32)
+		if nil != e {
+			resolved, le := lookup(item)
+			if nil != le {
+				return nil, le
+			}
+			id, e = strconv.ParseUint(resolved, 10, 32)
+		}
+		if nil != e {
+			return nil, fmt.Errorf("%s: %w", item, e)
+		}
+		ret[uint32(id)] = struct{}{}
+	}
+	return ret, nil
+}
+
+func lookupUid(name string) (string, error) {
+	u, e := user.Lookup(name)
+	if nil != e {
+		return "", e
+	}
+	return u.Uid, nil
+}
+
+func lookupGid(name string) (string, error) {
+	g, e := user.LookupGroup(name)
+	if nil != e {
+		return "", e
+	}
+	return g.Gid, nil
+}
+
+type OwnerFilter struct {
+	Users  IDSet
+	Groups IDSet
+}
+
+func ParseOwnerFilter(users string, groups string) (OwnerFilter, error) {
+	u, e := parseIDs(users, lookupUid)
+	if nil != e {
+		return OwnerFilter{}, e
+	}
+
+	g, e := parseIDs(groups, lookupGid)
+	return OwnerFilter{Users: u, Groups: g}, e
+}
+
+func (f OwnerFilter) IsEmpty() bool {
+	return 0 == len(f.Users) && 0 == len(f.Groups)
+}
+
+func (f OwnerFilter) Match(o Owner) bool {
+	_, byUser := f.Users[o.Uid]
+	_, byGroup := f.Groups[o.Gid]
+	return f.IsEmpty() || byUser || byGroup
+}
+
+func (f OwnerFilter) Check(fi fs.FileInfo) error {
+	if f.IsEmpty() {
+		return nil
+	}
+
+	o, found := FileInfoToOwner(fi)
+	switch {
+	case !found:
+		return ErrOwnerUnknown
+	case f.Match(o):
+		return nil
+	default:
+		return ErrNotOwned
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package names2stats
+
+import (
+	"io/fs"
+)
+
+func FileInfoToOwner(_ fs.FileInfo) (Owner, bool) {
+	return Owner{}, false
+}
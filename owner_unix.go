@@ -0,0 +1,16 @@
+//go:build unix
+
+package names2stats
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+func FileInfoToOwner(fi fs.FileInfo) (Owner, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return Owner{}, false
+	}
+	return Owner{Uid: st.Uid, Gid: st.Gid}, true
+}
@@ -0,0 +1,471 @@
+package names2stats
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"iter"
+	"math/bits"
+)
+
+const ParquetRowGroupRowsDefault int = 128 * 1024
+
+const parquetMagic string = "PAR1"
+
+const (
+	thriftTrue   byte = 1
+	thriftFalse  byte = 2
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+const (
+	parquetTypeInt64     int32 = 2
+	parquetTypeByteArray int32 = 6
+
+	parquetRequired int32 = 0
+
+	parquetConvertedUtf8            int32 = 0
+	parquetConvertedTimestampMicros int32 = 10
+
+	parquetEncodingPlain         int32 = 0
+	parquetEncodingRle           int32 = 3
+	parquetEncodingRleDictionary int32 = 8
+
+	parquetCodecUncompressed int32 = 0
+
+	parquetPageData       int32 = 0
+	parquetPageDictionary int32 = 2
+
+	parquetLogicalString    int16 = 1
+	parquetLogicalTimestamp int16 = 8
+	parquetTimeUnitMicros   int16 = 2
+
+	parquetFileMetaDataVersion int32 = 1
+)
+
+const parquetCreatedBy string = "go-names2stats"
+
+type thriftCompact struct {
+	buf   []byte
+	last  int16
+	stack []int16
+}
+
+func (t *thriftCompact) field(id int16, typ byte) {
+	var delta int16 = id - t.last
+	switch {
+	case 0 < delta && delta <= 15:
+		t.buf = append(t.buf, byte(delta)<<4|typ)
+	default:
+		t.buf = append(t.buf, typ)
+		t.buf = binary.AppendVarint(t.buf, int64(id))
+	}
+	t.last = id
+}
+
+func (t *thriftCompact) begin() {
+	t.stack = append(t.stack, t.last)
+	t.last = 0
+}
+
+func (t *thriftCompact) end() {
+	t.buf = append(t.buf, 0)
+	t.last = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+func (t *thriftCompact) i32(id int16, v int32) {
+	t.field(id, thriftI32)
+	t.buf = binary.AppendVarint(t.buf, int64(v))
+}
+
+func (t *thriftCompact) i64(id int16, v int64) {
+	t.field(id, thriftI64)
+	t.buf = binary.AppendVarint(t.buf, v)
+}
+
+func (t *thriftCompact) str(id int16, s string) {
+	t.field(id, thriftBinary)
+	t.buf = binary.AppendUvarint(t.buf, uint64(len(s)))
+	t.buf = append(t.buf, s...)
+}
+
+func (t *thriftCompact) boolean(id int16, v bool) {
+	switch v {
+	case true:
+		t.field(id, thriftTrue)
+	default:
+		t.field(id, thriftFalse)
+	}
+}
+
+func (t *thriftCompact) structure(id int16, body func()) {
+	t.field(id, thriftStruct)
+	t.begin()
+	body()
+	t.end()
+}
+
+func (t *thriftCompact) list(id int16, elem byte, n int) {
+	t.field(id, thriftList)
+	switch {
+	case n < 15:
+		t.buf = append(t.buf, byte(n)<<4|elem)
+	default:
+		t.buf = append(t.buf, 0xf0|elem)
+		t.buf = binary.AppendUvarint(t.buf, uint64(n))
+	}
+}
+
+func (t *thriftCompact) i32List(id int16, items ...int32) {
+	t.list(id, thriftI32, len(items))
+	for _, v := range items {
+		t.buf = binary.AppendVarint(t.buf, int64(v))
+	}
+}
+
+func (t *thriftCompact) strList(id int16, items ...string) {
+	t.list(id, thriftBinary, len(items))
+	for _, s := range items {
+		t.buf = binary.AppendUvarint(t.buf, uint64(len(s)))
+		t.buf = append(t.buf, s...)
+	}
+}
+
+func (t *thriftCompact) structList(id int16, n int, body func(int)) {
+	t.list(id, thriftStruct, n)
+	for i := range n {
+		t.begin()
+		body(i)
+		t.end()
+	}
+}
+
+type parquetColumn struct {
+	Name      string
+	Type      int32
+	Converted int32
+	Logical   func(*thriftCompact)
+}
+
+var parquetColumns []parquetColumn = []parquetColumn{
+	{
+		Name:      "path",
+		Type:      parquetTypeByteArray,
+		Converted: parquetConvertedUtf8,
+		Logical: func(t *thriftCompact) {
+			t.structure(parquetLogicalString, func() {})
+		},
+	},
+	{Name: "size", Type: parquetTypeInt64, Converted: -1},
+	{
+		Name:      "modified_time",
+		Type:      parquetTypeInt64,
+		Converted: parquetConvertedTimestampMicros,
+		Logical: func(t *thriftCompact) {
+			t.structure(parquetLogicalTimestamp, func() {
+				t.boolean(1, true)
+				t.structure(2, func() {
+					t.structure(parquetTimeUnitMicros, func() {})
+				})
+			})
+		},
+	},
+	{
+		Name:      "file_type",
+		Type:      parquetTypeByteArray,
+		Converted: parquetConvertedUtf8,
+		Logical: func(t *thriftCompact) {
+			t.structure(parquetLogicalString, func() {})
+		},
+	},
+}
+
+type parquetChunk struct {
+	Encodings  []int32
+	NumValues  int64
+	Size       int64
+	DictOffset int64
+	DataOffset int64
+	HasDict    bool
+}
+
+type parquetRowGroup struct {
+	Chunks []parquetChunk
+	Rows   int64
+	Size   int64
+}
+
+type ParquetWriter struct {
+	RowGroupRows int
+	FileTypes    FileTypeToString
+
+	w         *bufio.Writer
+	offset    int64
+	rows      []BasicStat
+	rowGroups []parquetRowGroup
+	numRows   int64
+}
+
+func (c FileTypeToString) NewParquetWriter(wtr io.Writer) *ParquetWriter {
+	return &ParquetWriter{
+		RowGroupRows: ParquetRowGroupRowsDefault,
+		FileTypes:    c,
+		w:            bufio.NewWriter(wtr),
+	}
+}
+
+func (p *ParquetWriter) begin() error {
+	if 0 != p.offset {
+		return nil
+	}
+	n, e := p.w.WriteString(parquetMagic)
+	p.offset += int64(n)
+	return e
+}
+
+func (p *ParquetWriter) write(b []byte) error {
+	n, e := p.w.Write(b)
+	p.offset += int64(n)
+	return e
+}
+
+func pageHeader(typ int32, size int, body func(*thriftCompact)) []byte {
+	var t thriftCompact
+	t.i32(1, typ)
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	body(&t)
+	t.buf = append(t.buf, 0)
+	return t.buf
+}
+
+func dataPageHeader(n int, size int, encoding int32) []byte {
+	return pageHeader(parquetPageData, size, func(t *thriftCompact) {
+		t.structure(5, func() {
+			t.i32(1, int32(n))
+			t.i32(2, encoding)
+			t.i32(3, parquetEncodingRle)
+			t.i32(4, parquetEncodingRle)
+		})
+	})
+}
+
+func dictPageHeader(n int, size int) []byte {
+	return pageHeader(parquetPageDictionary, size, func(t *thriftCompact) {
+		t.structure(7, func() {
+			t.i32(1, int32(n))
+			t.i32(2, parquetEncodingPlain)
+		})
+	})
+}
+
+func appendPlainBytes(buf []byte, s string) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(s)))
+	return append(buf, s...)
+}
+
+func appendRleRuns(buf []byte, indices []uint32, width int) []byte {
+	var valueBytes int = (width + 7) / 8
+	for i := 0; i < len(indices); {
+		var j int = i + 1
+		for j < len(indices) && indices[j] == indices[i] {
+			j++
+		}
+		buf = binary.AppendUvarint(buf, uint64(j-i)<<1)
+		for k := range valueBytes {
+			buf = append(buf, byte(indices[i]>>(8*k)))
+		}
+		i = j
+	}
+	return buf
+}
+
+func (p *ParquetWriter) plainChunk(n int, values []byte) (parquetChunk, error) {
+	var chunk parquetChunk = parquetChunk{
+		Encodings:  []int32{parquetEncodingPlain},
+		NumValues:  int64(n),
+		DataOffset: p.offset,
+	}
+	var header []byte = dataPageHeader(n, len(values), parquetEncodingPlain)
+	chunk.Size = int64(len(header) + len(values))
+	return chunk, errors.Join(p.write(header), p.write(values))
+}
+
+func (p *ParquetWriter) dictChunk(values []string) (parquetChunk, error) {
+	var dict []string
+	var ids map[string]uint32 = map[string]uint32{}
+	var indices []uint32 = make([]uint32, 0, len(values))
+	for _, v := range values {
+		id, found := ids[v]
+		if !found {
+			id = uint32(len(dict))
+			ids[v] = id
+			dict = append(dict, v)
+		}
+		indices = append(indices, id)
+	}
+
+	var plain []byte
+	for _, v := range dict {
+		plain = appendPlainBytes(plain, v)
+	}
+
+	var width int = max(1, bits.Len(uint(len(dict)-1)))
+	var data []byte = appendRleRuns([]byte{byte(width)}, indices, width)
+
+	var chunk parquetChunk = parquetChunk{
+		Encodings:  []int32{parquetEncodingPlain, parquetEncodingRleDictionary},
+		NumValues:  int64(len(values)),
+		DictOffset: p.offset,
+		HasDict:    true,
+	}
+
+	var dictHeader []byte = dictPageHeader(len(dict), len(plain))
+	e := errors.Join(p.write(dictHeader), p.write(plain))
+	if nil != e {
+		return chunk, e
+	}
+
+	chunk.DataOffset = p.offset
+	var header []byte = dataPageHeader(len(values), len(data), parquetEncodingRleDictionary)
+	chunk.Size = int64(len(dictHeader) + len(plain) + len(header) + len(data))
+	return chunk, errors.Join(p.write(header), p.write(data))
+}
+
+func (p *ParquetWriter) flushRowGroup() error {
+	if 0 == len(p.rows) {
+		return nil
+	}
+
+	e := p.begin()
+	if nil != e {
+		return e
+	}
+
+	var n int = len(p.rows)
+	var paths []byte
+	var sizes []byte = make([]byte, 0, 8*n)
+	var mtimes []byte = make([]byte, 0, 8*n)
+	var types []string = make([]string, 0, n)
+	for _, s := range p.rows {
+		paths = appendPlainBytes(paths, s.Path)
+		sizes = binary.LittleEndian.AppendUint64(sizes, uint64(s.Size))
+		mtimes = binary.LittleEndian.AppendUint64(mtimes, uint64(s.Modified))
+		types = append(types, p.FileTypes(s.FileType))
+	}
+
+	var group parquetRowGroup = parquetRowGroup{Rows: int64(n)}
+	for _, build := range []func() (parquetChunk, error){
+		func() (parquetChunk, error) { return p.plainChunk(n, paths) },
+		func() (parquetChunk, error) { return p.plainChunk(n, sizes) },
+		func() (parquetChunk, error) { return p.plainChunk(n, mtimes) },
+		func() (parquetChunk, error) { return p.dictChunk(types) },
+	} {
+		chunk, e := build()
+		if nil != e {
+			return e
+		}
+		group.Chunks = append(group.Chunks, chunk)
+		group.Size += chunk.Size
+	}
+
+	p.rowGroups = append(p.rowGroups, group)
+	p.numRows += int64(n)
+	p.rows = p.rows[:0]
+	return nil
+}
+
+func (p *ParquetWriter) Write(s BasicStat) error {
+	p.rows = append(p.rows, s)
+	if len(p.rows) < p.RowGroupRows {
+		return nil
+	}
+	return p.flushRowGroup()
+}
+
+func (p *ParquetWriter) footer() []byte {
+	var t thriftCompact
+	t.i32(1, parquetFileMetaDataVersion)
+
+	t.structList(2, 1+len(parquetColumns), func(i int) {
+		if 0 == i {
+			t.str(4, "schema")
+			t.i32(5, int32(len(parquetColumns)))
+			return
+		}
+		var col parquetColumn = parquetColumns[i-1]
+		t.i32(1, col.Type)
+		t.i32(3, parquetRequired)
+		t.str(4, col.Name)
+		if 0 <= col.Converted {
+			t.i32(6, col.Converted)
+		}
+		if nil != col.Logical {
+			t.structure(10, func() { col.Logical(&t) })
+		}
+	})
+
+	t.i64(3, p.numRows)
+
+	t.structList(4, len(p.rowGroups), func(i int) {
+		var group parquetRowGroup = p.rowGroups[i]
+		t.structList(1, len(group.Chunks), func(j int) {
+			var chunk parquetChunk = group.Chunks[j]
+			var col parquetColumn = parquetColumns[j]
+			var first int64 = chunk.DataOffset
+			if chunk.HasDict {
+				first = chunk.DictOffset
+			}
+			t.i64(2, first)
+			t.structure(3, func() {
+				t.i32(1, col.Type)
+				t.i32List(2, chunk.Encodings...)
+				t.strList(3, col.Name)
+				t.i32(4, parquetCodecUncompressed)
+				t.i64(5, chunk.NumValues)
+				t.i64(6, chunk.Size)
+				t.i64(7, chunk.Size)
+				t.i64(9, chunk.DataOffset)
+				if chunk.HasDict {
+					t.i64(11, chunk.DictOffset)
+				}
+			})
+		})
+		t.i64(2, group.Size)
+		t.i64(3, group.Rows)
+	})
+
+	t.str(6, parquetCreatedBy)
+	t.buf = append(t.buf, 0)
+	return t.buf
+}
+
+func (p *ParquetWriter) Close() error {
+	e := errors.Join(p.begin(), p.flushRowGroup())
+	if nil != e {
+		return e
+	}
+
+	var meta []byte = p.footer()
+	meta = binary.LittleEndian.AppendUint32(meta, uint32(len(meta)))
+	meta = append(meta, parquetMagic...)
+
+	e = p.write(meta)
+	if nil != e {
+		return e
+	}
+	return p.w.Flush()
+}
+
+func (c FileTypeToString) BasicStatsToParquetWriter(
+	wtr io.Writer,
+) func(iter.Seq2[BasicStat, error]) error {
+	return c.ParquetSink().BasicStatsToWriter(context.Background(), wtr)
+}
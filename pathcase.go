@@ -0,0 +1,35 @@
+package names2stats
+
+import (
+	"cmp"
+	"strings"
+)
+
+type PathCase int
+
+const (
+	PathCaseSensitive PathCase = iota
+	PathCaseInsensitive
+)
+
+func (c PathCase) Fold(name string) string {
+	switch c {
+	case PathCaseInsensitive:
+		return strings.ToLower(name)
+	default:
+		return name
+	}
+}
+
+func (c PathCase) Equal(a, b string) bool {
+	switch c {
+	case PathCaseInsensitive:
+		return strings.EqualFold(a, b)
+	default:
+		return a == b
+	}
+}
+
+func (c PathCase) Compare(a, b string) int {
+	return cmp.Compare(c.Fold(a), c.Fold(b))
+}
@@ -0,0 +1,174 @@
+package pb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTruncated   error = errors.New("truncated protobuf message")
+	ErrInvalidWire error = errors.New("invalid protobuf wire type")
+)
+
+type FileType int32
+
+const (
+	FileTypeUnspecified FileType = 0
+	FileTypeRegular     FileType = 100
+	FileTypeSymlink     FileType = 102
+	FileTypeCharacter   FileType = 103
+	FileTypeBlock       FileType = 104
+	FileTypeDirectory   FileType = 105
+	FileTypeFifo        FileType = 106
+	FileTypeSocket      FileType = 109
+)
+
+var fileTypeNames map[FileType]string = map[FileType]string{
+	FileTypeUnspecified: "FILE_TYPE_UNSPECIFIED",
+	FileTypeRegular:     "FILE_TYPE_REGULAR",
+	FileTypeSymlink:     "FILE_TYPE_SYMLINK",
+	FileTypeCharacter:   "FILE_TYPE_CHARACTER",
+	FileTypeBlock:       "FILE_TYPE_BLOCK",
+	FileTypeDirectory:   "FILE_TYPE_DIRECTORY",
+	FileTypeFifo:        "FILE_TYPE_FIFO",
+	FileTypeSocket:      "FILE_TYPE_SOCKET",
+}
+
+func (t FileType) String() string {
+	name, found := fileTypeNames[t]
+	switch found {
+	case true:
+		return name
+	default:
+		return fmt.Sprintf("%d", int32(t))
+	}
+}
+
+type BasicStat struct {
+	Path               string
+	Size               int64
+	ModifiedUnixtimeUs int64
+	FileType           FileType
+}
+
+const (
+	wireVarint uint64 = 0
+	wireI64    uint64 = 1
+	wireBytes  uint64 = 2
+	wireI32    uint64 = 5
+)
+
+const (
+	fieldPath               uint64 = 1
+	fieldSize               uint64 = 2
+	fieldModifiedUnixtimeUs uint64 = 3
+	fieldFileType           uint64 = 4
+)
+
+func appendTag(buf []byte, field uint64, wire uint64) []byte {
+	return binary.AppendUvarint(buf, field<<3|wire)
+}
+
+func appendVarintField(buf []byte, field uint64, v uint64) []byte {
+	if 0 == v {
+		return buf
+	}
+	return binary.AppendUvarint(appendTag(buf, field, wireVarint), v)
+}
+
+func (s *BasicStat) AppendMarshal(buf []byte) []byte {
+	if "" != s.Path {
+		buf = appendTag(buf, fieldPath, wireBytes)
+		buf = binary.AppendUvarint(buf, uint64(len(s.Path)))
+		buf = append(buf, s.Path...)
+	}
+	buf = appendVarintField(buf, fieldSize, uint64(s.Size))
+	buf = appendVarintField(buf, fieldModifiedUnixtimeUs, uint64(s.ModifiedUnixtimeUs))
+	buf = appendVarintField(buf, fieldFileType, uint64(int64(s.FileType)))
+	return buf
+}
+
+func (s *BasicStat) Marshal() ([]byte, error) {
+	return s.AppendMarshal(nil), nil
+}
+
+func uvarint(b []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, ErrTruncated
+	}
+	return v, b[n:], nil
+}
+
+func skipField(b []byte, wire uint64) ([]byte, error) {
+	switch wire {
+	case wireVarint:
+		_, rest, e := uvarint(b)
+		return rest, e
+	case wireI64:
+		if len(b) < 8 {
+			return nil, ErrTruncated
+		}
+		return b[8:], nil
+	case wireBytes:
+		n, rest, e := uvarint(b)
+		if nil != e {
+			return nil, e
+		}
+		if uint64(len(rest)) < n {
+			return nil, ErrTruncated
+		}
+		return rest[n:], nil
+	case wireI32:
+		if len(b) < 4 {
+			return nil, ErrTruncated
+		}
+		return b[4:], nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrInvalidWire, wire)
+	}
+}
+
+func (s *BasicStat) Unmarshal(b []byte) error {
+	*s = BasicStat{}
+	for 0 < len(b) {
+		tag, rest, e := uvarint(b)
+		if nil != e {
+			return e
+		}
+		var field uint64 = tag >> 3
+		var wire uint64 = tag & 7
+
+		switch {
+		case fieldPath == field && wireBytes == wire:
+			n, data, e := uvarint(rest)
+			if nil != e {
+				return e
+			}
+			if uint64(len(data)) < n {
+				return ErrTruncated
+			}
+			s.Path = string(data[:n])
+			b = data[n:]
+		case fieldSize == field && wireVarint == wire:
+			var v uint64
+			v, b, e = uvarint(rest)
+			s.Size = int64(v)
+		case fieldModifiedUnixtimeUs == field && wireVarint == wire:
+			var v uint64
+			v, b, e = uvarint(rest)
+			s.ModifiedUnixtimeUs = int64(v)
+		case fieldFileType == field && wireVarint == wire:
+			var v uint64
+			v, b, e = uvarint(rest)
+			s.FileType = FileType(int32(v))
+		default:
+			b, e = skipField(rest, wire)
+		}
+		if nil != e {
+			return e
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package pb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"iter"
+)
+
+const MessageSizeMax uint64 = 64 << 20
+
+var ErrMessageTooLarge error = errors.New("protobuf message too large")
+
+func AppendDelimited(buf []byte, s *BasicStat) []byte {
+	var start int = len(buf)
+	buf = s.AppendMarshal(buf)
+	var size []byte = binary.AppendUvarint(nil, uint64(len(buf)-start))
+	buf = append(buf, size...)
+	copy(buf[start+len(size):], buf[start:len(buf)-len(size)])
+	copy(buf[start:], size)
+	return buf
+}
+
+func WriteDelimited(wtr io.Writer) func(iter.Seq2[*BasicStat, error]) error {
+	return func(stats iter.Seq2[*BasicStat, error]) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		var buf []byte
+		for s, e := range stats {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			buf = AppendDelimited(buf[:0], s)
+			_, e = bw.Write(buf)
+			if nil != e {
+				return e
+			}
+		}
+		return bw.Flush()
+	}
+}
+
+func ReadDelimited(rdr io.Reader) iter.Seq2[*BasicStat, error] {
+	return func(yield func(*BasicStat, error) bool) {
+		var br *bufio.Reader = bufio.NewReader(rdr)
+		var buf []byte
+		for {
+			size, e := binary.ReadUvarint(br)
+			if io.EOF == e {
+				return
+			}
+			if nil == e && MessageSizeMax < size {
+				e = ErrMessageTooLarge
+			}
+			if nil != e {
+				yield(nil, e)
+				return
+			}
+
+			buf = append(buf[:0], make([]byte, size)...)
+			_, e = io.ReadFull(br, buf)
+			if errors.Is(e, io.EOF) {
+				e = io.ErrUnexpectedEOF
+			}
+
+			var s BasicStat
+			if nil == e {
+				e = s.Unmarshal(buf)
+			}
+			if nil != e {
+				yield(nil, e)
+				return
+			}
+
+			if !yield(&s, nil) {
+				return
+			}
+		}
+	}
+}
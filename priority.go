@@ -0,0 +1,90 @@
+package names2stats
+
+import (
+	"reflect"
+)
+
+type laneItem struct {
+	tagged TaggedName
+	err    error
+}
+
+func pumpLane(lane TaggedNameIter, done <-chan struct{}) <-chan laneItem {
+	var ch chan laneItem = make(chan laneItem)
+	go func() {
+		defer close(ch)
+		for tagged, e := range lane {
+			select {
+			case ch <- laneItem{tagged: tagged, err: e}:
+			case <-done:
+				return
+			}
+			if nil != e {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+func nextLane(lanes []<-chan laneItem) (int, laneItem, bool) {
+	for i, ch := range lanes {
+		if nil == ch {
+			continue
+		}
+		select {
+		case item, ok := <-ch:
+			return i, item, ok
+		default:
+		}
+	}
+
+	var cases []reflect.SelectCase = make([]reflect.SelectCase, 0, len(lanes))
+	for _, ch := range lanes {
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(ch),
+		})
+	}
+	i, v, ok := reflect.Select(cases)
+	if !ok {
+		return i, laneItem{}, false
+	}
+	item, _ := v.Interface().(laneItem)
+	return i, item, true
+}
+
+func PriorityTagged(lanes ...TaggedNameIter) TaggedNameIter {
+	if 1 == len(lanes) {
+		return lanes[0]
+	}
+
+	return func(yield func(TaggedName, error) bool) {
+		var done chan struct{} = make(chan struct{})
+		defer close(done)
+
+		var chans []<-chan laneItem = make([]<-chan laneItem, 0, len(lanes))
+		for _, lane := range lanes {
+			chans = append(chans, pumpLane(lane, done))
+		}
+
+		for open := len(chans); 0 < open; {
+			i, item, ok := nextLane(chans)
+			if !ok {
+				chans[i] = nil
+				open--
+				continue
+			}
+			if !yield(item.tagged, item.err) || nil != item.err {
+				return
+			}
+		}
+	}
+}
+
+func (s NameSources) PriorityTagged(bulk NameSources) TaggedNameIter {
+	if 0 == len(s) {
+		return bulk.ToTagged()
+	}
+	return PriorityTagged(s.ToTagged(), bulk.ToTagged())
+}
@@ -0,0 +1,59 @@
+package names2stats
+
+import (
+	"io"
+	"iter"
+
+	"github.com/takanoriyanagitani/go-names2stats/pb"
+)
+
+func (b BasicStat) ToProto() *pb.BasicStat {
+	return &pb.BasicStat{
+		Path:               b.Path,
+		Size:               b.Size,
+		ModifiedUnixtimeUs: int64(b.Modified),
+		FileType:           pb.FileType(b.FileType),
+	}
+}
+
+func BasicStatFromProto(p *pb.BasicStat) BasicStat {
+	return BasicStat{
+		Path:     p.Path,
+		Size:     p.Size,
+		Modified: UnixtimeUs(p.ModifiedUnixtimeUs),
+		FileType: FileType(p.FileType),
+	}
+}
+
+func BasicStatsToProtoWriter(wtr io.Writer) func(iter.Seq2[BasicStat, error]) error {
+	return func(stats iter.Seq2[BasicStat, error]) error {
+		return pb.WriteDelimited(wtr)(func(yield func(*pb.BasicStat, error) bool) {
+			for s, e := range stats {
+				if !yield(s.ToProto(), e) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func (m FileTypeToStringMap) RecordsToProtoWriter(wtr io.Writer) func(RecordIter) error {
+	var s2t StringToFileType = m.ToStringToFileType()
+	return func(records RecordIter) error {
+		return BasicStatsToProtoWriter(wtr)(s2t.RecordsToBasicStats(records))
+	}
+}
+
+func ProtoReaderToBasicStats(rdr io.Reader) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for p, e := range pb.ReadDelimited(rdr) {
+			var s BasicStat
+			if nil == e {
+				s = BasicStatFromProto(p)
+			}
+			if !yield(s, e) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package names2stats
+
+import (
+	"slices"
+)
+
+var DirentFields []string = []string{"path", "file_type"}
+
+func FieldsNeedStat(keys []string) bool {
+	if 0 == len(keys) {
+		return true
+	}
+	for _, key := range keys {
+		if !slices.Contains(DirentFields, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Enrichers) Project(keys []string) Enrichers {
+	if 0 == len(keys) {
+		return s
+	}
+
+	var ret Enrichers = make(Enrichers, 0, len(s))
+	for _, enricher := range s {
+		describer, ok := enricher.(FieldDescriber)
+		if !ok {
+			ret = append(ret, enricher)
+			continue
+		}
+
+		var wanted bool = slices.ContainsFunc(
+			describer.DescribeFields(),
+			func(f FieldSchema) bool { return slices.Contains(keys, f.Key) },
+		)
+		if wanted {
+			ret = append(ret, enricher)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,192 @@
+package names2stats
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const RecordTypeQuotaAlert string = "quota_alert"
+
+var (
+	ErrInvalidQuota  error = errors.New("invalid quota")
+	ErrQuotaExceeded error = errors.New("quota exceeded")
+)
+
+var byteSizeUnits map[string]int64 = map[string]int64{
+	"":  1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+	"P": 1 << 50,
+}
+
+func ParseByteSize(s string) (int64, error) {
+	var upper string = strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(s)), "B")
+	upper = strings.TrimSuffix(upper, "I")
+
+	var num string = strings.TrimRight(upper, "KMGTP")
+	unit, found := byteSizeUnits[upper[len(num):]]
+	if !found {
+		return 0, fmt.Errorf("%w: size %q", ErrInvalidQuota, s)
+	}
+
+	f, e := strconv.ParseFloat(num, 64)
+	if nil != e || f < 0 {
+		return 0, fmt.Errorf("%w: size %q", ErrInvalidQuota, s)
+	}
+	return int64(f * float64(unit)), nil
+}
+
+type Quota struct {
+	Pattern  GlobPattern
+	MaxFiles int64
+	MaxBytes int64
+}
+
+func parseQuota(raw string) (Quota, error) {
+	var q Quota = Quota{MaxFiles: -1, MaxBytes: -1}
+
+	pat, limits, found := strings.Cut(raw, "=")
+	if !found || "" == strings.TrimSpace(pat) {
+		return q, fmt.Errorf("%w: %q: want pattern=files:N,bytes:SIZE", ErrInvalidQuota, raw)
+	}
+	q.Pattern = GlobPattern(strings.TrimSpace(pat))
+	e := q.Pattern.Validate()
+	if nil != e {
+		return q, fmt.Errorf("%w: %q: %w", ErrInvalidQuota, raw, e)
+	}
+
+	for limit := range strings.SplitSeq(limits, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(limit), ":")
+		switch key {
+		case "files":
+			q.MaxFiles, e = strconv.ParseInt(val, 10, 64)
+		case "bytes":
+			q.MaxBytes, e = ParseByteSize(val)
+		default:
+			e = fmt.Errorf("unknown limit %q", key)
+		}
+		if nil != e {
+			return q, fmt.Errorf("%w: %q: %w", ErrInvalidQuota, raw, e)
+		}
+	}
+	return q, nil
+}
+
+func ParseQuotas(raw string) ([]Quota, error) {
+	var ret []Quota
+	for item := range strings.SplitSeq(raw, ";") {
+		if "" == strings.TrimSpace(item) {
+			continue
+		}
+		q, e := parseQuota(item)
+		if nil != e {
+			return nil, e
+		}
+		ret = append(ret, q)
+	}
+	return ret, nil
+}
+
+type DirUsage struct {
+	Files int64
+	Bytes int64
+}
+
+type QuotaCheck struct {
+	Quotas []Quota
+	Case   PathCase
+	Usage  map[string]*DirUsage
+}
+
+func NewQuotaCheck(quotas []Quota, c PathCase) *QuotaCheck {
+	return &QuotaCheck{Quotas: quotas, Case: c, Usage: map[string]*DirUsage{}}
+}
+
+func (q *QuotaCheck) Observe(s BasicStat) {
+	if FileTypeFldr == s.FileType {
+		return
+	}
+	for dir := path.Dir(path.Clean(s.Path)); ; dir = path.Dir(dir) {
+		u, found := q.Usage[dir]
+		if !found {
+			u = &DirUsage{}
+			q.Usage[dir] = u
+		}
+		u.Files++
+		u.Bytes += s.Size
+		if "." == dir || "/" == dir {
+			return
+		}
+	}
+}
+
+func (q *QuotaCheck) Tally(
+	stats iter.Seq2[BasicStat, error],
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for s, e := range stats {
+			if nil == e {
+				q.Observe(s)
+			}
+			if !yield(s, e) {
+				return
+			}
+		}
+	}
+}
+
+func quotaAlert(dir string, q Quota, limit string, allowed int64, actual int64) Record {
+	return Record{
+		{Key: "record_type", Value: RecordTypeQuotaAlert},
+		{Key: "dir", Value: dir},
+		{Key: "pattern", Value: string(q.Pattern)},
+		{Key: "limit", Value: limit},
+		{Key: "max", Value: allowed},
+		{Key: "actual", Value: actual},
+	}
+}
+
+func (q Quota) MatchDir(dir string, c PathCase) bool {
+	if "." == dir {
+		var cleaned string = path.Clean(string(q.Pattern))
+		return "." == cleaned || "**" == cleaned
+	}
+	matched, _ := q.Pattern.MatchCase(dir, c)
+	return matched
+}
+
+func (q *QuotaCheck) Alerts() []Record {
+	var ret []Record
+	var dirs []string = slices.SortedFunc(maps.Keys(q.Usage), q.Case.Compare)
+	for _, dir := range dirs {
+		var u *DirUsage = q.Usage[dir]
+		for _, quota := range q.Quotas {
+			if !quota.MatchDir(dir, q.Case) {
+				continue
+			}
+			if 0 <= quota.MaxFiles && quota.MaxFiles < u.Files {
+				ret = append(ret, quotaAlert(dir, quota, "files", quota.MaxFiles, u.Files))
+			}
+			if 0 <= quota.MaxBytes && quota.MaxBytes < u.Bytes {
+				ret = append(ret, quotaAlert(dir, quota, "bytes", quota.MaxBytes, u.Bytes))
+			}
+		}
+	}
+	return ret
+}
+
+func (q *QuotaCheck) Err() error {
+	var alerts int = len(q.Alerts())
+	if 0 == alerts {
+		return nil
+	}
+	return fmt.Errorf("%w: %d alert(s)", ErrQuotaExceeded, alerts)
+}
@@ -0,0 +1,292 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"reflect"
+	"time"
+)
+
+type Field struct {
+	Key   string
+	Value any
+}
+
+type Record []Field
+
+func encodeJSON(buf *bytes.Buffer, enc *json.Encoder, v any) error {
+	e := enc.Encode(v)
+	if nil != e {
+		return e
+	}
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+func (r Record) appendJSON(buf *bytes.Buffer, escapeHTML bool) error {
+	var enc *json.Encoder = json.NewEncoder(buf)
+	enc.SetEscapeHTML(escapeHTML)
+
+	buf.WriteByte('{')
+	for i, f := range r {
+		if 0 < i {
+			buf.WriteByte(',')
+		}
+
+		e := encodeJSON(buf, enc, f.Key)
+		if nil != e {
+			return e
+		}
+		buf.WriteByte(':')
+
+		nested, ok := f.Value.(Record)
+		switch ok {
+		case true:
+			e = nested.appendJSON(buf, escapeHTML)
+		default:
+			e = encodeJSON(buf, enc, f.Value)
+		}
+		if nil != e {
+			return e
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func (r Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e := r.appendJSON(&buf, true)
+	return buf.Bytes(), e
+}
+
+type unescapedRecord Record
+
+func (r unescapedRecord) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e := Record(r).appendJSON(&buf, false)
+	return buf.Bytes(), e
+}
+
+func (r Record) Get(key string) (any, bool) {
+	for _, f := range r {
+		if key == f.Key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (r Record) Keys() []string {
+	var ret []string = make([]string, 0, len(r))
+	for _, f := range r {
+		ret = append(ret, f.Key)
+	}
+	return ret
+}
+
+func (r Record) Set(key string, val any) Record {
+	for i, f := range r {
+		if key == f.Key {
+			r[i].Value = val
+			return r
+		}
+	}
+	return append(r, Field{Key: key, Value: val})
+}
+
+func (j BasicStatJson) ToRecord() Record {
+	return Record{
+		{Key: "path", Value: j.Path},
+		{Key: "size", Value: j.Size},
+		{Key: "modified_time", Value: j.Modified},
+		{Key: "file_type", Value: j.FileType},
+	}
+}
+
+type RecordIter iter.Seq2[Record, error]
+
+type RecordMapper func(Record) Record
+
+func (i RecordIter) Map(f RecordMapper) RecordIter {
+	return func(yield func(Record, error) bool) {
+		for rec, e := range i {
+			if nil != e {
+				yield(nil, e)
+				return
+			}
+			if !yield(f(rec), nil) {
+				return
+			}
+		}
+	}
+}
+
+func BasicStatsToEnriched(
+	stats iter.Seq2[BasicStat, error],
+) iter.Seq2[EnrichedStat, error] {
+	return func(yield func(EnrichedStat, error) bool) {
+		for s, e := range stats {
+			if !yield(EnrichedStat{BasicStat: s}, e) {
+				return
+			}
+		}
+	}
+}
+
+func (c FileTypeToString) EnrichedStatsToRecords(
+	stats iter.Seq2[EnrichedStat, error],
+) RecordIter {
+	return func(yield func(Record, error) bool) {
+		for s, e := range stats {
+			if nil != e {
+				yield(nil, e)
+				return
+			}
+			if !yield(s.ToRecord(c), nil) {
+				return
+			}
+		}
+	}
+}
+
+type JsonOptions struct {
+	Indent       string
+	NoHTMLEscape bool
+	Canonical    bool
+	Flush        bool
+}
+
+func (o JsonOptions) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+
+		var enc *json.Encoder = json.NewEncoder(bw)
+		enc.SetIndent("", o.Indent)
+		enc.SetEscapeHTML(!o.NoHTMLEscape)
+		var line []byte
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			var v any = rec
+			if o.NoHTMLEscape {
+				v = unescapedRecord(rec)
+			}
+
+			switch o.Canonical {
+			case true:
+				line, e = AppendCanonicalJSON(line[:0], rec)
+				if nil == e {
+					_, e = bw.Write(append(line, '\n'))
+				}
+			default:
+				e = enc.Encode(v)
+			}
+			if nil == e && o.Flush {
+				e = bw.Flush()
+			}
+			if nil != e {
+				return e
+			}
+		}
+
+		return bw.Flush()
+	}
+}
+
+func RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	return JsonOptions{}.RecordsToWriter(wtr)
+}
+
+func RecordsToFlushingWriter(wtr io.Writer) func(RecordIter) error {
+	return JsonOptions{Flush: true}.RecordsToWriter(wtr)
+}
+
+func RecordsToStdout(records RecordIter) error {
+	return RecordsToWriter(os.Stdout)(records)
+}
+
+var ErrUnknownEmptyPolicy error = errors.New("unknown empty value policy")
+
+type EmptyPolicy int
+
+const (
+	EmptyAsZero EmptyPolicy = iota
+	EmptyOmit
+	EmptyNull
+)
+
+func ParseEmptyPolicy(s string) (EmptyPolicy, error) {
+	switch s {
+	case "zero":
+		return EmptyAsZero, nil
+	case "omit":
+		return EmptyOmit, nil
+	case "null":
+		return EmptyNull, nil
+	default:
+		return EmptyAsZero, fmt.Errorf("%w: %s", ErrUnknownEmptyPolicy, s)
+	}
+}
+
+func IsEmptyValue(val any) bool {
+	switch v := val.(type) {
+	case nil:
+		return true
+	case time.Time:
+		return v.IsZero() || 0 == v.UnixMicro()
+	default:
+		return reflect.ValueOf(val).IsZero()
+	}
+}
+
+func (p EmptyPolicy) Apply(rec Record) Record {
+	if EmptyAsZero == p {
+		return rec
+	}
+
+	var ret Record = make(Record, 0, len(rec))
+	for _, f := range rec {
+		switch {
+		case !IsEmptyValue(f.Value):
+			ret = append(ret, f)
+		case EmptyNull == p:
+			ret = append(ret, Field{Key: f.Key, Value: nil})
+		}
+	}
+	return ret
+}
+
+func AppendFields(fields Record) RecordMapper {
+	return func(rec Record) Record {
+		for _, f := range fields {
+			rec = rec.Set(f.Key, f.Value)
+		}
+		return rec
+	}
+}
+
+func ProjectFields(keys []string) RecordMapper {
+	return func(rec Record) Record {
+		if !IsStatRecord(rec) {
+			return rec
+		}
+
+		var ret Record = make(Record, 0, len(keys))
+		for _, key := range keys {
+			val, found := rec.Get(key)
+			if found {
+				ret = append(ret, Field{Key: key, Value: val})
+			}
+		}
+		return ret
+	}
+}
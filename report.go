@@ -0,0 +1,68 @@
+package names2stats
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"time"
+)
+
+var ErrUnknownPreset error = errors.New("unknown report preset")
+
+type Report interface {
+	Filter(Root, iter.Seq2[BasicStat, error]) iter.Seq2[BasicStat, error]
+	Summary() []Record
+}
+
+type ReportConfig struct {
+	Now      time.Time
+	StaleAge time.Duration
+	Top      int
+}
+
+var ReportPresets map[string]func(ReportConfig) Report = map[string]func(
+	ReportConfig,
+) Report{
+	"empty": func(ReportConfig) Report { return &EmptyReport{} },
+	"largest-dirs": func(c ReportConfig) Report {
+		return NewLargestDirsReport(cmp.Or(c.Top, TopDirsDefault))
+	},
+	"newest-mtime": func(ReportConfig) Report { return NewNewestMtimeReport() },
+	"secrets":      func(ReportConfig) Report { return NewSecretReport() },
+	"stale": func(c ReportConfig) Report {
+		return NewStaleReport(c.Now, cmp.Or(c.StaleAge, StaleAgeDefault))
+	},
+}
+
+func ReportPresetNames() []string {
+	return slices.Sorted(maps.Keys(ReportPresets))
+}
+
+func (c ReportConfig) ParseReportPreset(name string) (Report, error) {
+	newReport, found := ReportPresets[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPreset, name)
+	}
+	return newReport(c), nil
+}
+
+func (i RecordIter) AppendAll(last func() []Record) RecordIter {
+	return func(yield func(Record, error) bool) {
+		for rec, e := range i {
+			if !yield(rec, e) {
+				return
+			}
+			if nil != e {
+				return
+			}
+		}
+		for _, rec := range last() {
+			if !yield(rec, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package names2stats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+var ErrInvalidRotation error = errors.New("invalid rotation")
+
+type Rotation struct {
+	Pattern    string
+	MaxBytes   int64
+	MaxRecords int64
+}
+
+func ParseRotationPattern(s string) (string, error) {
+	var verbs int = strings.Count(s, "%") - 2*strings.Count(s, "%%")
+	if 1 != verbs || strings.Contains(fmt.Sprintf(s, 1), "%!") {
+		return "", fmt.Errorf(
+			"%w: %q: want one integer verb(e.g. stats-%%04d.jsonl)",
+			ErrInvalidRotation,
+			s,
+		)
+	}
+	return s, nil
+}
+
+func (r Rotation) Name(i int) string { return fmt.Sprintf(r.Pattern, i) }
+
+func (r Rotation) full(records int64, written int64) bool {
+	return (0 < r.MaxRecords && r.MaxRecords <= records) ||
+		(0 < r.MaxBytes && r.MaxBytes <= written)
+}
+
+type countingWriter struct {
+	io.Writer
+	n *int64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, e := c.Writer.Write(p)
+	*c.n += int64(n)
+	return n, e
+}
+
+func (r Rotation) RecordsToFiles(
+	newSink func(io.Writer) func(RecordIter) error,
+) func(RecordIter) error {
+	return func(records RecordIter) error {
+		next, stop := iter.Pull2(iter.Seq2[Record, error](records))
+		defer stop()
+
+		rec, err, ok := next()
+		for i := 1; 1 == i || ok; i++ {
+			f, e := StageFile(r.Name(i))
+			if nil != e {
+				return e
+			}
+
+			var written, count int64
+			var chunk RecordIter = func(yield func(Record, error) bool) {
+				for ok && !r.full(count, written) {
+					count++
+					var more bool = yield(rec, err)
+					rec, err, ok = next()
+					if !more {
+						return
+					}
+				}
+			}
+
+			e = newSink(countingWriter{Writer: f, n: &written})(chunk)
+			if nil != e {
+				return errors.Join(e, f.Abort(e))
+			}
+
+			e = f.Commit()
+			if nil != e {
+				return fmt.Errorf("%s: %w", f.Name, e)
+			}
+		}
+		return nil
+	}
+}
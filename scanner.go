@@ -0,0 +1,136 @@
+package names2stats
+
+import (
+	"errors"
+	"io/fs"
+	"iter"
+	"os"
+	"sync"
+)
+
+var ErrScannerClosed error = errors.New("scanner closed")
+
+type scannerRoot struct {
+	Root
+	info  fs.FileInfo
+	refs  int
+	stale bool
+}
+
+func (r *scannerRoot) closeIfUnused() error {
+	if !r.stale || 0 < r.refs {
+		return nil
+	}
+	return r.Close()
+}
+
+type Scanner struct {
+	Dir RootDirname
+
+	mu     sync.Mutex
+	cur    *scannerRoot
+	closed bool
+}
+
+func (d RootDirname) openScannerRoot() (*scannerRoot, error) {
+	rt, e := d.ToRoot()
+	if nil != e {
+		return nil, e
+	}
+
+	fi, e := rt.Stat(".")
+	if nil != e {
+		return nil, errors.Join(e, rt.Close())
+	}
+	return &scannerRoot{Root: Root{rt}, info: fi}, nil
+}
+
+func (d RootDirname) NewScanner() (*Scanner, error) {
+	cur, e := d.openScannerRoot()
+	if nil != e {
+		return nil, e
+	}
+	return &Scanner{Dir: d, cur: cur}, nil
+}
+
+func (s *Scanner) replaced() bool {
+	fi, e := os.Stat(string(s.Dir))
+	if nil != e {
+		return false
+	}
+	return !os.SameFile(fi, s.cur.info)
+}
+
+func (s *Scanner) acquire() (*scannerRoot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrScannerClosed
+	}
+
+	if s.replaced() {
+		next, e := s.Dir.openScannerRoot()
+		if nil != e {
+			return nil, e
+		}
+		s.cur.stale = true
+		_ = s.cur.closeIfUnused()
+		s.cur = next
+	}
+
+	s.cur.refs++
+	return s.cur, nil
+}
+
+func (s *Scanner) release(r *scannerRoot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.refs--
+	return r.closeIfUnused()
+}
+
+func (s *Scanner) WithRoot(f func(Root) error) error {
+	r, e := s.acquire()
+	if nil != e {
+		return e
+	}
+	return errors.Join(f(r.Root), s.release(r))
+}
+
+func (s *Scanner) NamesToBasicStats(names NameIter) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		var empty BasicStat
+
+		r, e := s.acquire()
+		if nil != e {
+			yield(empty, e)
+			return
+		}
+		defer func() { _ = s.release(r) }()
+
+		var stat FilenameToBasicStat = r.ToFilenameToBasicStat()
+		for name, e := range names {
+			if nil != e {
+				yield(empty, e)
+				return
+			}
+			if !yield(stat(name)) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Scanner) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.cur.stale = true
+	return s.cur.closeIfUnused()
+}
@@ -0,0 +1,157 @@
+package names2stats
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+type FieldSchema struct {
+	Key      string
+	Types    []string
+	Format   string
+	Enum     []string
+	Required bool
+}
+
+type FieldDescriber interface {
+	DescribeFields() []FieldSchema
+}
+
+func JsonTypeOf(val any) string {
+	switch v := val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "integer"
+	case json.Number:
+		_, e := v.Int64()
+		if nil == e {
+			return "integer"
+		}
+		return "number"
+	case float32, float64:
+		return "number"
+	case map[string]any, Record:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return "string"
+	}
+}
+
+func (m FileTypeToStringMap) Names() []string {
+	return slices.Sorted(maps.Values(m))
+}
+
+func BasicFieldSchemas(m FileTypeToStringMap) []FieldSchema {
+	return []FieldSchema{
+		{Key: "path", Types: []string{"string"}, Required: true},
+		{Key: "size", Types: []string{"integer"}, Required: true},
+		{
+			Key:      "modified_time",
+			Types:    []string{"string"},
+			Format:   "date-time",
+			Required: true,
+		},
+		{
+			Key:      "file_type",
+			Types:    []string{"string"},
+			Enum:     m.Names(),
+			Required: true,
+		},
+	}
+}
+
+func (s Enrichers) DescribeFields() (fields []FieldSchema, open bool) {
+	for _, enricher := range s {
+		describer, ok := enricher.(FieldDescriber)
+		if !ok {
+			open = true
+			continue
+		}
+		fields = append(fields, describer.DescribeFields()...)
+	}
+	return fields, open
+}
+
+func (c ComputedFields) DescribeFields() []FieldSchema {
+	var ret []FieldSchema = make([]FieldSchema, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		ret = append(ret, FieldSchema{
+			Key:      f.Name,
+			Types:    []string{"number", "string"},
+			Required: true,
+		})
+	}
+	return ret
+}
+
+type RecordSchema struct {
+	Fields []FieldSchema
+	Open   bool
+}
+
+func (s RecordSchema) ToJsonSchema(p EmptyPolicy, k KeyCase) Record {
+	return s.ToJsonSchemaRenamed(p, k, nil)
+}
+
+func (s RecordSchema) ToJsonSchemaRenamed(
+	p EmptyPolicy,
+	k KeyCase,
+	r KeyRenames,
+) Record {
+	var props Record
+	var required []string = []string{}
+	for _, f := range s.Fields {
+		var key string = r.Convert(k, f.Key)
+
+		var types []string = f.Types
+		if EmptyNull == p {
+			types = append(slices.Clone(types), "null")
+		}
+
+		var prop Record
+		switch len(types) {
+		case 1:
+			prop = prop.Set("type", types[0])
+		default:
+			prop = prop.Set("type", types)
+		}
+		if "" != f.Format {
+			prop = prop.Set("format", f.Format)
+		}
+		if 0 < len(f.Enum) {
+			var enum []any = make([]any, 0, len(f.Enum)+1)
+			for _, val := range f.Enum {
+				enum = append(enum, val)
+			}
+			if EmptyNull == p {
+				enum = append(enum, nil)
+			}
+			prop = prop.Set("enum", enum)
+		}
+
+		props = props.Set(key, prop)
+		if f.Required && EmptyOmit != p {
+			required = append(required, key)
+		}
+	}
+	if nil == props {
+		props = Record{}
+	}
+
+	return Record{
+		{Key: "$schema", Value: "https://json-schema.org/draft/2020-12/schema"},
+		{Key: "title", Value: "names2stats record"},
+		{Key: "type", Value: "object"},
+		{Key: "properties", Value: props},
+		{Key: "required", Value: required},
+		{Key: "additionalProperties", Value: s.Open},
+	}
+}
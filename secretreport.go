@@ -0,0 +1,159 @@
+package names2stats
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"iter"
+	"path"
+	"strings"
+)
+
+const RecordTypeSecretSummary string = "secret_summary"
+
+const (
+	SeverityMedium string = "medium"
+	SeverityHigh   string = "high"
+)
+
+var SecretPatterns []string = []string{
+	"id_rsa",
+	"id_dsa",
+	"id_ecdsa",
+	"id_ed25519",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"*.pfx",
+	".env",
+	".env.*",
+	".netrc",
+	".pgpass",
+}
+
+func MatchSecretPattern(name string) (string, bool) {
+	var base string = path.Base(name)
+	for _, pat := range SecretPatterns {
+		matched, _ := path.Match(pat, base)
+		if matched {
+			return pat, true
+		}
+	}
+	return "", false
+}
+
+func PermToSeverity(perm fs.FileMode) (string, bool) {
+	switch {
+	case 0 != perm&0o004:
+		return SeverityHigh, true
+	case 0 != perm&0o040:
+		return SeverityMedium, true
+	default:
+		return "", false
+	}
+}
+
+type secretFinding struct {
+	rule     string
+	severity string
+	mode     fs.FileMode
+}
+
+type SecretReport struct {
+	findings map[string]secretFinding
+	counts   map[string]int64
+}
+
+func NewSecretReport() *SecretReport {
+	return &SecretReport{
+		findings: map[string]secretFinding{},
+		counts:   map[string]int64{},
+	}
+}
+
+func (rep *SecretReport) Filter(
+	r Root,
+	stats iter.Seq2[BasicStat, error],
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for s, e := range stats {
+			if nil != e {
+				yield(s, e)
+				return
+			}
+
+			if FileTypeRglr != s.FileType {
+				continue
+			}
+
+			rule, found := MatchSecretPattern(s.Path)
+			if !found {
+				continue
+			}
+
+			fi, e := r.NameToInfo(s.Path)
+			if nil != e {
+				yield(s, e)
+				return
+			}
+
+			var perm fs.FileMode = fi.Mode().Perm()
+			severity, exposed := PermToSeverity(perm)
+			if !exposed {
+				continue
+			}
+
+			rep.findings[s.Path] = secretFinding{
+				rule:     rule,
+				severity: severity,
+				mode:     perm,
+			}
+			rep.counts[severity]++
+
+			if !yield(s, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (rep *SecretReport) Enrich(
+	_ context.Context,
+	_ Root,
+	stat BasicStat,
+) (EnrichedStat, error) {
+	var ret EnrichedStat = EnrichedStat{BasicStat: stat}
+
+	f, found := rep.findings[stat.Path]
+	if !found {
+		return ret, nil
+	}
+
+	ret.Extra = map[string]any{
+		"secret_rule": f.rule,
+		"severity":    f.severity,
+		"secret_mode": fmt.Sprintf("%04o", uint32(f.mode)),
+	}
+	return ret, nil
+}
+
+func (rep *SecretReport) DescribeFields() []FieldSchema {
+	return []FieldSchema{
+		{Key: "secret_rule", Types: []string{"string"}, Enum: SecretPatterns},
+		{
+			Key:   "severity",
+			Types: []string{"string"},
+			Enum:  []string{SeverityMedium, SeverityHigh},
+		},
+		{Key: "secret_mode", Types: []string{"string"}},
+	}
+}
+
+func (rep *SecretReport) Summary() []Record {
+	return []Record{{
+		{Key: "record_type", Value: RecordTypeSecretSummary},
+		{Key: "high", Value: rep.counts[SeverityHigh]},
+		{Key: "medium", Value: rep.counts[SeverityMedium]},
+		{Key: "patterns", Value: strings.Join(SecretPatterns, ",")},
+	}}
+}
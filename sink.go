@@ -0,0 +1,154 @@
+package names2stats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"slices"
+)
+
+var ErrUnknownSink error = errors.New("unknown sink")
+
+type Sink interface {
+	Write(ctx context.Context, s BasicStat) error
+	Close() error
+}
+
+type StatSink struct {
+	Stat func(BasicStat) error
+	Done func() error
+	Fail func(cause error) error
+}
+
+func (s StatSink) Write(ctx context.Context, b BasicStat) error {
+	e := ctx.Err()
+	if nil != e {
+		return e
+	}
+	return s.Stat(b)
+}
+
+func (s StatSink) Close() error { return s.Done() }
+
+func (s StatSink) Abort(cause error) error {
+	if nil == s.Fail {
+		return s.Done()
+	}
+	return s.Fail(cause)
+}
+
+func abortSink(s Sink, cause error) error {
+	a, ok := s.(interface{ Abort(cause error) error })
+	switch ok {
+	case true:
+		return a.Abort(cause)
+	default:
+		return s.Close()
+	}
+}
+
+type SinkFactory func(ctx context.Context, wtr io.Writer) (Sink, error)
+
+func (f SinkFactory) BasicStatsToWriter(
+	ctx context.Context,
+	wtr io.Writer,
+) func(iter.Seq2[BasicStat, error]) error {
+	return func(stats iter.Seq2[BasicStat, error]) error {
+		sink, e := f(ctx, wtr)
+		if nil != e {
+			return e
+		}
+
+		for s, e := range stats {
+			if nil == e {
+				e = sink.Write(ctx, s)
+			}
+			if nil != e {
+				return errors.Join(e, abortSink(sink, e))
+			}
+		}
+		return sink.Close()
+	}
+}
+
+type SinkRegistry map[string]SinkFactory
+
+func (r SinkRegistry) Register(name string, f SinkFactory) SinkRegistry {
+	var ret SinkRegistry = maps.Clone(r)
+	if nil == ret {
+		ret = SinkRegistry{}
+	}
+	ret[name] = f
+	return ret
+}
+
+func (r SinkRegistry) Lookup(name string) (SinkFactory, error) {
+	f, found := r[name]
+	switch found {
+	case true:
+		return f, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSink, name)
+	}
+}
+
+func (r SinkRegistry) Names() []string { return slices.Sorted(maps.Keys(r)) }
+
+func (c FileTypeToString) ArrowSink(batchRows int, file bool) SinkFactory {
+	return func(_ context.Context, wtr io.Writer) (Sink, error) {
+		var a *ArrowWriter = c.NewArrowWriter(wtr)
+		a.File = file
+		if 0 < batchRows {
+			a.BatchRows = batchRows
+		}
+		return StatSink{
+			Stat: a.Write,
+			Done: a.Close,
+			Fail: func(error) error { return a.w.Flush() },
+		}, nil
+	}
+}
+
+func (c FileTypeToString) AvroSink(codec string) SinkFactory {
+	return func(_ context.Context, wtr io.Writer) (Sink, error) {
+		var a *AvroWriter = c.NewAvroWriter(wtr, codec)
+		return StatSink{
+			Stat: a.Write,
+			Done: a.Close,
+			Fail: func(error) error { return a.w.Flush() },
+		}, nil
+	}
+}
+
+func (c FileTypeToString) ParquetSink() SinkFactory {
+	return func(_ context.Context, wtr io.Writer) (Sink, error) {
+		var p *ParquetWriter = c.NewParquetWriter(wtr)
+		return StatSink{
+			Stat: p.Write,
+			Done: p.Close,
+			Fail: func(error) error { return nil },
+		}, nil
+	}
+}
+
+func (o SqliteOptions) Sink() SinkFactory {
+	return func(_ context.Context, wtr io.Writer) (Sink, error) {
+		var w *SqliteWriter = o.NewWriter(wtr)
+		return StatSink{Stat: w.Write, Done: w.Close, Fail: w.Abort}, nil
+	}
+}
+
+func (c FileTypeToString) DefaultSinks() SinkRegistry {
+	return SinkRegistry{
+		"arrow":   c.ArrowSink(0, false),
+		"feather": c.ArrowSink(0, true),
+		"ncdu":    NcduOptions{}.Sink(),
+		"avro":    c.AvroSink(AvroCodecNull),
+		"ber":     Asn1LayoutCompat.BerSink(),
+		"parquet": c.ParquetSink(),
+		"sql":     SqliteOptions{}.Sink(),
+	}
+}
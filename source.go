@@ -0,0 +1,353 @@
+package names2stats
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownSource error = errors.New("unknown source")
+	ErrInvalidSource error = errors.New("invalid source")
+)
+
+type Source interface {
+	Names(ctx context.Context) NameIter
+}
+
+type SourceFunc func(ctx context.Context) NameIter
+
+func (f SourceFunc) Names(ctx context.Context) NameIter { return f(ctx) }
+
+func ReaderSource(rdr io.Reader, d NameDelim) Source {
+	return SourceFunc(func(_ context.Context) NameIter {
+		return d.ReaderToNameIter(rdr)
+	})
+}
+
+func (p InputPath) Source(d NameDelim) Source {
+	return SourceFunc(func(_ context.Context) NameIter { return p.ToNamesDelim(d) })
+}
+
+func (c Command) Source(d NameDelim) Source {
+	return SourceFunc(func(ctx context.Context) NameIter { return c.ToNamesDelim(ctx, d) })
+}
+
+var ErrFSNotReady error = errors.New("file system not ready")
+
+type DeferredFS struct{ fsys fs.FS }
+
+func (d *DeferredFS) Set(fsys fs.FS) { d.fsys = fsys }
+
+func (d *DeferredFS) get(op, name string) (fs.FS, error) {
+	if nil == d.fsys {
+		return nil, &fs.PathError{Op: op, Path: name, Err: ErrFSNotReady}
+	}
+	return d.fsys, nil
+}
+
+func (d *DeferredFS) Open(name string) (fs.File, error) {
+	fsys, e := d.get("open", name)
+	if nil != e {
+		return nil, e
+	}
+	return fsys.Open(name)
+}
+
+func (d *DeferredFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	fsys, e := d.get("readdir", name)
+	if nil != e {
+		return nil, e
+	}
+	return fs.ReadDir(fsys, name)
+}
+
+func (d *DeferredFS) Stat(name string) (fs.FileInfo, error) {
+	fsys, e := d.get("stat", name)
+	if nil != e {
+		return nil, e
+	}
+	return fs.Stat(fsys, name)
+}
+
+type WalkSource struct {
+	FS  fs.FS
+	Dir string
+}
+
+func (w WalkSource) Names(ctx context.Context) NameIter {
+	return func(yield func(string, error) bool) {
+		var stop error = errors.New("stop")
+		e := fs.WalkDir(w.FS, w.Dir, func(name string, _ fs.DirEntry, e error) error {
+			if nil == e {
+				e = ctx.Err()
+			}
+			if nil != e {
+				return e
+			}
+			if !yield(name, nil) {
+				return stop
+			}
+			return nil
+		})
+		if nil != e && !errors.Is(e, stop) {
+			yield("", e)
+		}
+	}
+}
+
+type GlobSource struct {
+	FS      fs.FS
+	Pattern GlobPattern
+	Case    PathCase
+}
+
+func (g GlobSource) Names(_ context.Context) NameIter {
+	return g.Pattern.ExpandCase(g.FS, g.Case)
+}
+
+func GlobSourceFactory(fsys fs.FS, c PathCase) SourceFactory {
+	return func(arg string) (Source, error) {
+		if "" == arg {
+			return nil, errors.New("missing pattern")
+		}
+		var p GlobPattern = GlobPattern(arg)
+		return GlobSource{FS: fsys, Pattern: p, Case: c}, p.Validate()
+	}
+}
+
+const (
+	SocketHandshakeTimeoutDefault time.Duration = 10 * time.Second
+	SocketIdleTimeoutDefault      time.Duration = 5 * time.Minute
+)
+
+type SocketSource struct {
+	Network          string
+	Address          string
+	Delim            NameDelim
+	TLS              *tls.Config
+	HandshakeTimeout time.Duration
+	IdleTimeout      time.Duration
+	Rejected         func(addr net.Addr, e error)
+}
+
+func (s SocketSource) reject(conn net.Conn, e error) {
+	if nil != s.Rejected {
+		s.Rejected(conn.RemoteAddr(), e)
+	}
+}
+
+type idleReader struct {
+	conn    net.Conn
+	timeout time.Duration
+	failed  error
+}
+
+func (r *idleReader) Read(p []byte) (int, error) {
+	e := r.conn.SetReadDeadline(time.Now().Add(r.timeout))
+	if nil != e {
+		return 0, e
+	}
+	n, e := r.conn.Read(p)
+	if nil != e && !errors.Is(e, io.EOF) {
+		r.failed = e
+	}
+	return n, e
+}
+
+func (s SocketSource) serve(
+	ctx context.Context,
+	conn net.Conn,
+	yield func(string, error) bool,
+) (stop bool) {
+	defer conn.Close()
+	var unwatch func() bool = context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer unwatch()
+
+	var timeout time.Duration = s.IdleTimeout
+	if timeout <= 0 {
+		timeout = SocketIdleTimeoutDefault
+	}
+
+	var rdr *idleReader = &idleReader{conn: conn, timeout: timeout}
+	for name, e := range s.Delim.ReaderToNameIter(rdr) {
+		switch {
+		case nil != ctx.Err():
+			yield("", ctx.Err())
+			return true
+		case nil != rdr.failed:
+			// the scanner hands out the unterminated rest after a failed read
+			s.reject(conn, rdr.failed)
+			return false
+		case nil != e:
+			s.reject(conn, e)
+			return false
+		case !yield(name, nil):
+			return true
+		}
+	}
+	return false
+}
+
+func (s SocketSource) handshake(ctx context.Context, conn net.Conn) bool {
+	tc, ok := conn.(*tls.Conn)
+	if !ok {
+		return true
+	}
+
+	var timeout time.Duration = s.HandshakeTimeout
+	if timeout <= 0 {
+		timeout = SocketHandshakeTimeoutDefault
+	}
+
+	e := tc.SetDeadline(time.Now().Add(timeout))
+	if nil == e {
+		e = tc.HandshakeContext(ctx)
+	}
+	if nil == e {
+		e = tc.SetDeadline(time.Time{})
+	}
+	if nil == e {
+		return true
+	}
+	s.reject(conn, e)
+	_ = conn.Close()
+	return false
+}
+
+func (s SocketSource) Names(ctx context.Context) NameIter {
+	return func(yield func(string, error) bool) {
+		var lc net.ListenConfig
+		l, e := lc.Listen(ctx, s.Network, s.Address)
+		if nil != e {
+			yield("", e)
+			return
+		}
+		if nil != s.TLS {
+			l = tls.NewListener(l, s.TLS)
+		}
+
+		lctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			<-lctx.Done()
+			_ = l.Close()
+		}()
+
+		for {
+			conn, e := l.Accept()
+			if nil != e {
+				if nil != ctx.Err() {
+					e = ctx.Err()
+				}
+				yield("", e)
+				return
+			}
+			if !s.handshake(ctx, conn) {
+				continue
+			}
+			if s.serve(ctx, conn, yield) {
+				return
+			}
+		}
+	}
+}
+
+type SourceFactory func(arg string) (Source, error)
+
+type SourceRegistry map[string]SourceFactory
+
+func (r SourceRegistry) Register(kind string, f SourceFactory) SourceRegistry {
+	var ret SourceRegistry = maps.Clone(r)
+	if nil == ret {
+		ret = SourceRegistry{}
+	}
+	ret[kind] = f
+	return ret
+}
+
+func (r SourceRegistry) Lookup(kind string) (SourceFactory, error) {
+	f, found := r[kind]
+	switch found {
+	case true:
+		return f, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSource, kind)
+	}
+}
+
+func (r SourceRegistry) Names() []string { return slices.Sorted(maps.Keys(r)) }
+
+func (r SourceRegistry) Open(ctx context.Context, spec string) (NameSource, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	f, e := r.Lookup(kind)
+	if nil != e {
+		return NameSource{}, e
+	}
+
+	src, e := f(arg)
+	if nil != e {
+		return NameSource{}, fmt.Errorf("%w: %q: %w", ErrInvalidSource, spec, e)
+	}
+	return NameSource{Tag: spec, Names: src.Names(ctx)}, nil
+}
+
+func (r SourceRegistry) OpenAll(ctx context.Context, specs []string) (NameSources, error) {
+	var ret NameSources = make(NameSources, 0, len(specs))
+	for _, spec := range specs {
+		src, e := r.Open(ctx, spec)
+		if nil != e {
+			return nil, e
+		}
+		ret = append(ret, src)
+	}
+	return ret, nil
+}
+
+func (s SocketSource) Factory() SourceFactory {
+	return func(arg string) (Source, error) {
+		network, address, found := strings.Cut(arg, ":")
+		if !found || "" == address {
+			return nil, errors.New("want socket:NETWORK:ADDRESS")
+		}
+		s.Network, s.Address = network, address
+		return s, nil
+	}
+}
+
+func DefaultSources(fsys fs.FS, d NameDelim) SourceRegistry {
+	return SourceRegistry{
+		"stdin": func(_ string) (Source, error) { return ReaderSource(os.Stdin, d), nil },
+		"file": func(arg string) (Source, error) {
+			if "" == arg {
+				return nil, errors.New("missing path")
+			}
+			return InputPath(arg).Source(d), nil
+		},
+		"exec": func(arg string) (Source, error) {
+			var c Command = CommandLineToCommand(arg)
+			if 0 == len(c) {
+				return nil, ErrEmptyCommand
+			}
+			return c.Source(d), nil
+		},
+		"walk": func(arg string) (Source, error) {
+			if "" == arg {
+				arg = "."
+			}
+			return WalkSource{FS: fsys, Dir: path.Clean(arg)}, nil
+		},
+		"glob":   GlobSourceFactory(fsys, PathCaseSensitive),
+		"socket": SocketSource{Delim: d}.Factory(),
+	}
+}
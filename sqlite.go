@@ -0,0 +1,131 @@
+package names2stats
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+const (
+	SqliteTableDefault     string = "stats"
+	SqliteBatchRowsDefault int    = 1000
+)
+
+func SqliteQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func SqliteQuoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+type SqliteOptions struct {
+	Table     string
+	BatchRows int
+}
+
+func (o SqliteOptions) table() string {
+	if "" == o.Table {
+		return SqliteQuoteIdent(SqliteTableDefault)
+	}
+	return SqliteQuoteIdent(o.Table)
+}
+
+func (o SqliteOptions) CreateTable() string {
+	return "CREATE TABLE IF NOT EXISTS " + o.table() + "(" +
+		"path TEXT PRIMARY KEY, " +
+		"size INTEGER, " +
+		"modified INTEGER, " +
+		"file_type INTEGER);\n"
+}
+
+func (o SqliteOptions) Upsert(s BasicStat) string {
+	return fmt.Sprintf(
+		"INSERT INTO %s(path, size, modified, file_type) VALUES(%s, %d, %d, %d) "+
+			"ON CONFLICT(path) DO UPDATE SET "+
+			"size=excluded.size, "+
+			"modified=excluded.modified, "+
+			"file_type=excluded.file_type;\n",
+		o.table(),
+		SqliteQuote(s.Path),
+		s.Size,
+		int64(s.Modified),
+		int(s.FileType),
+	)
+}
+
+type SqliteWriter struct {
+	SqliteOptions
+
+	w       *bufio.Writer
+	started bool
+	pending int
+}
+
+func (o SqliteOptions) NewWriter(wtr io.Writer) *SqliteWriter {
+	if o.BatchRows <= 0 {
+		o.BatchRows = SqliteBatchRowsDefault
+	}
+	return &SqliteWriter{SqliteOptions: o, w: bufio.NewWriter(wtr)}
+}
+
+func (w *SqliteWriter) Write(s BasicStat) error {
+	if !w.started {
+		w.started = true
+		_, e := w.w.WriteString(w.CreateTable())
+		if nil != e {
+			return e
+		}
+	}
+
+	if 0 == w.pending {
+		_, e := w.w.WriteString("BEGIN;\n")
+		if nil != e {
+			return e
+		}
+	}
+
+	_, e := w.w.WriteString(w.Upsert(s))
+	if nil != e {
+		return e
+	}
+
+	w.pending++
+	if w.pending < w.BatchRows {
+		return nil
+	}
+	return w.commit()
+}
+
+func (w *SqliteWriter) commit() error {
+	w.pending = 0
+	_, e := w.w.WriteString("COMMIT;\n")
+	return e
+}
+
+func (w *SqliteWriter) Close() error {
+	if !w.started {
+		_, e := w.w.WriteString(w.CreateTable())
+		if nil != e {
+			return e
+		}
+	}
+	if 0 < w.pending {
+		e := w.commit()
+		if nil != e {
+			return e
+		}
+	}
+	return w.w.Flush()
+}
+
+func (w *SqliteWriter) Abort(_ error) error { return w.w.Flush() }
+
+func (o SqliteOptions) BasicStatsToSqlWriter(
+	wtr io.Writer,
+) func(iter.Seq2[BasicStat, error]) error {
+	return o.Sink().BasicStatsToWriter(context.Background(), wtr)
+}
@@ -0,0 +1,88 @@
+package names2stats
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"path"
+	"slices"
+	"time"
+)
+
+const RecordTypeStaleDir string = "stale_dir"
+
+var StaleAgeDefault time.Duration = 365 * 24 * time.Hour
+
+type staleDir struct {
+	files  int64
+	bytes  int64
+	oldest UnixtimeUs
+}
+
+type StaleReport struct {
+	Cutoff UnixtimeUs
+
+	dirs map[string]*staleDir
+}
+
+func NewStaleReport(now time.Time, age time.Duration) *StaleReport {
+	return &StaleReport{
+		Cutoff: UnixtimeUs(now.Add(-age).UnixMicro()),
+		dirs:   map[string]*staleDir{},
+	}
+}
+
+func (rep *StaleReport) Filter(
+	_ Root,
+	stats iter.Seq2[BasicStat, error],
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for s, e := range stats {
+			if nil != e {
+				yield(s, e)
+				return
+			}
+
+			if FileTypeRglr != s.FileType || rep.Cutoff <= s.Modified {
+				continue
+			}
+
+			var dir string = path.Dir(path.Clean(s.Path))
+			d, found := rep.dirs[dir]
+			if !found {
+				d = &staleDir{oldest: s.Modified}
+				rep.dirs[dir] = d
+			}
+			d.files++
+			d.bytes += s.Size
+			d.oldest = min(d.oldest, s.Modified)
+
+			if !yield(s, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (rep *StaleReport) Summary() []Record {
+	var dirs []string = slices.SortedFunc(maps.Keys(rep.dirs), func(a, b string) int {
+		return cmp.Or(
+			cmp.Compare(rep.dirs[b].bytes, rep.dirs[a].bytes),
+			cmp.Compare(a, b),
+		)
+	})
+
+	var ret []Record = make([]Record, 0, len(dirs))
+	for _, dir := range dirs {
+		var d *staleDir = rep.dirs[dir]
+		ret = append(ret, Record{
+			{Key: "record_type", Value: RecordTypeStaleDir},
+			{Key: "dir", Value: dir},
+			{Key: "files", Value: d.files},
+			{Key: "bytes", Value: d.bytes},
+			{Key: "oldest_modified", Value: d.oldest.ToTime()},
+			{Key: "cutoff", Value: rep.Cutoff.ToTime()},
+		})
+	}
+	return ret
+}
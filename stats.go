@@ -0,0 +1,74 @@
+package names2stats
+
+import (
+	"cmp"
+	"slices"
+)
+
+func (b BasicStat) Compare(o BasicStat) int {
+	return cmp.Or(
+		cmp.Compare(b.Path, o.Path),
+		cmp.Compare(b.Size, o.Size),
+		cmp.Compare(b.Modified, o.Modified),
+		cmp.Compare(b.FileType, o.FileType),
+	)
+}
+
+func (b BasicStat) CompareCase(o BasicStat, c PathCase) int {
+	return cmp.Or(
+		c.Compare(b.Path, o.Path),
+		b.Compare(o),
+	)
+}
+
+func (b BasicStat) EqualCase(o BasicStat, c PathCase) bool {
+	return c.Equal(b.Path, o.Path) &&
+		b.Size == o.Size &&
+		b.Modified == o.Modified &&
+		b.FileType == o.FileType
+}
+
+func (b BasicStats) Canonical() BasicStats {
+	return b.CanonicalCase(PathCaseSensitive)
+}
+
+func (b BasicStats) CanonicalCase(c PathCase) BasicStats {
+	var sorted BasicStats = slices.Clone(b)
+	slices.SortFunc(sorted, func(x, y BasicStat) int {
+		return x.CompareCase(y, c)
+	})
+	return slices.CompactFunc(sorted, func(x, y BasicStat) bool {
+		return x.EqualCase(y, c)
+	})
+}
+
+func (b BasicStats) ToIter() BasicStatIter {
+	return func(yield func(BasicStat, error) bool) {
+		for _, s := range b {
+			if !yield(s, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (i BasicStatIter) Canonical() BasicStatIter {
+	return i.CanonicalCase(PathCaseSensitive)
+}
+
+func (i BasicStatIter) CanonicalCase(c PathCase) BasicStatIter {
+	return func(yield func(BasicStat, error) bool) {
+		collected, e := i.Collect()
+		if nil != e {
+			var empty BasicStat
+			yield(empty, e)
+			return
+		}
+
+		for s, e := range BasicStats(collected).CanonicalCase(c).ToIter() {
+			if !yield(s, e) {
+				return
+			}
+		}
+	}
+}
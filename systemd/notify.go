@@ -0,0 +1,37 @@
+package systemd
+
+import (
+	"net"
+	"os"
+	"strings"
+)
+
+const (
+	StateReady    string = "READY=1"
+	StateStopping string = "STOPPING=1"
+)
+
+func Notify(state string) (bool, error) {
+	var name string = os.Getenv("NOTIFY_SOCKET")
+	if "" == name {
+		return false, nil
+	}
+
+	if strings.HasPrefix(name, "@") {
+		name = "\x00" + name[1:]
+	}
+
+	conn, e := net.DialUnix("unixgram", nil, &net.UnixAddr{
+		Name: name,
+		Net:  "unixgram",
+	})
+	if nil != e {
+		return false, e
+	}
+	defer conn.Close()
+
+	_, e = conn.Write([]byte(state))
+	return nil == e, e
+}
+
+func Status(msg string) (bool, error) { return Notify("STATUS=" + msg) }
@@ -0,0 +1,138 @@
+package names2stats
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const TableBatchRowsDefault int = 1000
+
+type TableOptions struct {
+	Columns   []string
+	NoHeader  bool
+	BatchRows int
+}
+
+type tableBatch struct {
+	columns []string
+	right   []bool
+	rows    [][]string
+}
+
+func (b *tableBatch) add(rec Record) {
+	var row []string = make([]string, 0, len(b.columns))
+	for i, col := range b.columns {
+		val, _ := rec.Get(col)
+		switch val.(type) {
+		case int64, int, float64:
+			b.right[i] = true
+		}
+		row = append(row, TsvValue(val))
+	}
+	b.rows = append(b.rows, row)
+}
+
+func (b *tableBatch) write(bw *bufio.Writer, header bool) error {
+	var widths []int = make([]int, len(b.columns))
+	var rows [][]string = b.rows
+	if header {
+		rows = append([][]string{b.columns}, rows...)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var line strings.Builder
+	for _, row := range rows {
+		line.Reset()
+		for i, cell := range row {
+			var pad string = strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			var last bool = len(row)-1 == i
+			switch {
+			case b.right[i]:
+				line.WriteString(pad + cell)
+			case last:
+				line.WriteString(cell)
+			default:
+				line.WriteString(cell + pad)
+			}
+			if !last {
+				line.WriteString("  ")
+			}
+		}
+		line.WriteByte('\n')
+
+		_, e := bw.WriteString(line.String())
+		if nil != e {
+			return e
+		}
+	}
+	b.rows = b.rows[:0]
+	return nil
+}
+
+func (o TableOptions) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	var batchRows int = o.BatchRows
+	if batchRows <= 0 {
+		batchRows = TableBatchRowsDefault
+	}
+
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		var batch *tableBatch
+		var batches int
+
+		var flush func() error = func() error {
+			if nil == batch || 0 == len(batch.rows) {
+				return nil
+			}
+			if 0 < batches {
+				_, e := bw.WriteString("\n")
+				if nil != e {
+					return e
+				}
+			}
+			batches++
+			return batch.write(bw, !o.NoHeader)
+		}
+
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, flush(), bw.Flush())
+			}
+
+			if !IsStatRecord(rec) {
+				continue
+			}
+
+			if nil == batch {
+				var columns []string = o.Columns
+				if nil == columns {
+					columns = rec.Keys()
+				}
+				batch = &tableBatch{columns: columns, right: make([]bool, len(columns))}
+			}
+
+			batch.add(rec)
+			if len(batch.rows) < batchRows {
+				continue
+			}
+
+			e = flush()
+			if nil != e {
+				return e
+			}
+		}
+
+		e := flush()
+		if nil != e {
+			return e
+		}
+		return bw.Flush()
+	}
+}
@@ -0,0 +1,117 @@
+package names2stats
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const TeeBufferDefault int = 256
+
+var ErrInvalidTee error = errors.New("invalid tee target")
+
+type TeeTarget struct {
+	Format string
+	Path   string
+}
+
+func ParseTeeTargets(s string) ([]TeeTarget, error) {
+	var ret []TeeTarget
+	for spec := range strings.SplitSeq(s, ";") {
+		spec = strings.TrimSpace(spec)
+		if "" == spec {
+			continue
+		}
+
+		format, path, found := strings.Cut(spec, ":")
+		if !found || "" == format || "" == path {
+			return nil, fmt.Errorf("%w: %q: want format:path", ErrInvalidTee, spec)
+		}
+		ret = append(ret, TeeTarget{Format: format, Path: path})
+	}
+	return ret, nil
+}
+
+type teeItem struct {
+	rec Record
+	err error
+}
+
+type teeLane struct {
+	items chan teeItem
+	done  chan struct{}
+	alive bool
+}
+
+func (l *teeLane) send(item teeItem) {
+	if !l.alive {
+		return
+	}
+	select {
+	case l.items <- item:
+	case <-l.done:
+		l.alive = false
+	}
+}
+
+func (l *teeLane) records(yield func(Record, error) bool) {
+	for item := range l.items {
+		if !yield(item.rec, item.err) {
+			return
+		}
+	}
+}
+
+func TeeRecords(sinks ...func(RecordIter) error) func(RecordIter) error {
+	if 1 == len(sinks) {
+		return sinks[0]
+	}
+
+	return func(records RecordIter) error {
+		var lanes []*teeLane = make([]*teeLane, 0, len(sinks))
+		var errs []error = make([]error, len(sinks))
+		var wg sync.WaitGroup
+		for i, sink := range sinks {
+			var lane *teeLane = &teeLane{
+				items: make(chan teeItem, TeeBufferDefault),
+				done:  make(chan struct{}),
+				alive: true,
+			}
+			lanes = append(lanes, lane)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer close(lane.done)
+				errs[i] = sink(lane.records)
+			}()
+		}
+
+		var fatal error
+		for rec, e := range records {
+			var alive bool
+			for _, lane := range lanes {
+				lane.send(teeItem{rec: rec, err: e})
+				alive = alive || lane.alive
+			}
+			if nil != e || !alive {
+				fatal = e
+				break
+			}
+		}
+
+		for _, lane := range lanes {
+			close(lane.items)
+		}
+		wg.Wait()
+
+		var ret []error = []error{fatal}
+		for _, e := range errs {
+			if nil == fatal || !errors.Is(e, fatal) {
+				ret = append(ret, e)
+			}
+		}
+		return errors.Join(ret...)
+	}
+}
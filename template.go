@@ -0,0 +1,67 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"text/template"
+)
+
+var RecordTemplateFuncs template.FuncMap = template.FuncMap{
+	"human": HumanSize,
+	"json": func(v any) (string, error) {
+		raw, e := json.Marshal(v)
+		return string(raw), e
+	},
+}
+
+type RecordTemplate struct {
+	*template.Template
+	newline bool
+}
+
+func ParseRecordTemplate(text string) (RecordTemplate, error) {
+	t, e := template.New("record").Funcs(RecordTemplateFuncs).Parse(text)
+	return RecordTemplate{
+		Template: t,
+		newline:  !strings.HasSuffix(text, "\n"),
+	}, e
+}
+
+func (r Record) ToMap() map[string]any {
+	var ret map[string]any = make(map[string]any, len(r))
+	for _, f := range r {
+		ret[f.Key] = f.Value
+	}
+	return ret
+}
+
+func (t RecordTemplate) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		var buf bytes.Buffer
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			buf.Reset()
+			e = t.Execute(&buf, rec.ToMap())
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+			if t.newline {
+				buf.WriteByte('\n')
+			}
+
+			_, e = bw.Write(buf.Bytes())
+			if nil != e {
+				return e
+			}
+		}
+		return bw.Flush()
+	}
+}
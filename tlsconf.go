@@ -0,0 +1,102 @@
+package names2stats
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+var ErrInvalidTLS error = errors.New("invalid tls configuration")
+
+var tlsVersions map[string]uint16 = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func ParseTLSVersion(s string) (uint16, error) {
+	v, found := tlsVersions[s]
+	switch found {
+	case true:
+		return v, nil
+	default:
+		return 0, fmt.Errorf("%w: tls version %q: want 1.0..1.3", ErrInvalidTLS, s)
+	}
+}
+
+type TLSOptions struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	MinVersion uint16
+}
+
+func (o TLSOptions) IsEmpty() bool {
+	return "" == o.CAFile && "" == o.CertFile && "" == o.KeyFile
+}
+
+func (o TLSOptions) pool() (*x509.CertPool, error) {
+	if "" == o.CAFile {
+		return nil, nil
+	}
+
+	pem, e := os.ReadFile(o.CAFile)
+	if nil != e {
+		return nil, e
+	}
+
+	var pool *x509.CertPool = x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: no certificates in %s", ErrInvalidTLS, o.CAFile)
+	}
+	return pool, nil
+}
+
+func (o TLSOptions) config() (*tls.Config, error) {
+	var conf *tls.Config = &tls.Config{MinVersion: o.MinVersion}
+	if 0 == conf.MinVersion {
+		conf.MinVersion = tls.VersionTLS12
+	}
+
+	if "" != o.CertFile || "" != o.KeyFile {
+		cert, e := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if nil != e {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTLS, e)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+	return conf, nil
+}
+
+func (o TLSOptions) ServerConfig() (*tls.Config, error) {
+	conf, e := o.config()
+	if nil != e {
+		return nil, e
+	}
+	if 0 == len(conf.Certificates) {
+		return nil, fmt.Errorf("%w: server needs a certificate and key", ErrInvalidTLS)
+	}
+
+	pool, e := o.pool()
+	if nil != e {
+		return nil, e
+	}
+	if nil != pool {
+		conf.ClientCAs = pool
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
+
+func (o TLSOptions) ClientConfig() (*tls.Config, error) {
+	conf, e := o.config()
+	if nil != e {
+		return nil, e
+	}
+
+	conf.RootCAs, e = o.pool()
+	return conf, e
+}
@@ -0,0 +1,124 @@
+package names2stats
+
+import (
+	"iter"
+	"maps"
+	"time"
+)
+
+const RecordTypeTrailer string = "trailer"
+
+type RunTrailer struct {
+	RecordType  string           `json:"record_type"`
+	EndTime     time.Time        `json:"end_time"`
+	DurationMs  int64            `json:"duration_ms"`
+	Records     int64            `json:"records"`
+	Errors      int64            `json:"errors"`
+	ErrorCounts map[string]int64 `json:"error_counts"`
+	Complete    bool             `json:"complete"`
+	StreamTotals
+}
+
+type StreamTotals struct {
+	TotalBytes int64            `json:"total_bytes"`
+	TypeCounts map[string]int64 `json:"type_counts"`
+}
+
+func (t *StreamTotals) Observe(s BasicStat, t2s FileTypeToString) {
+	if nil == t.TypeCounts {
+		t.TypeCounts = map[string]int64{}
+	}
+	t.TotalBytes += s.Size
+	t.TypeCounts[t2s(s.FileType)]++
+}
+
+func (t *StreamTotals) Tally(
+	stats iter.Seq2[BasicStat, error],
+	t2s FileTypeToString,
+) iter.Seq2[BasicStat, error] {
+	return func(yield func(BasicStat, error) bool) {
+		for s, e := range stats {
+			if nil == e {
+				t.Observe(s, t2s)
+			}
+			if !yield(s, e) {
+				return
+			}
+		}
+	}
+}
+
+func (t *StreamTotals) Clone() StreamTotals {
+	var counts map[string]int64 = maps.Clone(t.TypeCounts)
+	if nil == counts {
+		counts = map[string]int64{}
+	}
+	return StreamTotals{TotalBytes: t.TotalBytes, TypeCounts: counts}
+}
+
+func NewRunTrailer(
+	start time.Time,
+	records int64,
+	complete bool,
+	counts map[string]int64,
+	totals *StreamTotals,
+) RunTrailer {
+	var end time.Time = time.Now()
+
+	var errs map[string]int64 = maps.Clone(counts)
+	if nil == errs {
+		errs = map[string]int64{}
+	}
+
+	var total int64
+	for _, n := range errs {
+		total += n
+	}
+
+	return RunTrailer{
+		RecordType:   RecordTypeTrailer,
+		EndTime:      end,
+		DurationMs:   end.Sub(start).Milliseconds(),
+		Records:      records,
+		Errors:       total,
+		ErrorCounts:  errs,
+		Complete:     complete,
+		StreamTotals: totals.Clone(),
+	}
+}
+
+func (t RunTrailer) ToRecord() Record {
+	return Record{
+		{Key: "record_type", Value: t.RecordType},
+		{Key: "end_time", Value: t.EndTime},
+		{Key: "duration_ms", Value: t.DurationMs},
+		{Key: "records", Value: t.Records},
+		{Key: "errors", Value: t.Errors},
+		{Key: "error_counts", Value: t.ErrorCounts},
+		{Key: "complete", Value: t.Complete},
+		{Key: "total_bytes", Value: t.TotalBytes},
+		{Key: "type_counts", Value: t.TypeCounts},
+	}
+}
+
+func (i RecordIter) WithTrailer(
+	trailer func(records int64, fatal error) Record,
+) RecordIter {
+	return func(yield func(Record, error) bool) {
+		var records int64
+		for rec, e := range i {
+			if nil != e {
+				if yield(trailer(records, e), nil) {
+					yield(nil, e)
+				}
+				return
+			}
+
+			if !yield(rec, nil) {
+				return
+			}
+			records++
+		}
+		yield(trailer(records, nil), nil)
+	}
+}
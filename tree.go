@@ -0,0 +1,134 @@
+package names2stats
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+type DirNode struct {
+	Name     string
+	Stat     BasicStat
+	Seen     bool
+	Size     int64
+	Entries  int64
+	Newest   BasicStat
+	Parent   *DirNode
+	Children map[string]*DirNode
+}
+
+func NewDirTree() *DirNode {
+	return &DirNode{Name: ".", Children: map[string]*DirNode{}}
+}
+
+func (n *DirNode) child(name string) *DirNode {
+	c, found := n.Children[name]
+	if !found {
+		c = &DirNode{Name: name, Parent: n, Children: map[string]*DirNode{}}
+		n.Children[name] = c
+	}
+	return c
+}
+
+func (n *DirNode) addUp(size int64, entries int64) {
+	for p := n; nil != p; p = p.Parent {
+		p.Size += size
+		p.Entries += entries
+	}
+}
+
+func (n *DirNode) propagateNewest(s BasicStat) {
+	for p := n; nil != p; p = p.Parent {
+		if "" != p.Newest.Path && s.Modified <= p.Newest.Modified {
+			return
+		}
+		p.Newest = s
+	}
+}
+
+func (n *DirNode) Insert(s BasicStat) {
+	var node *DirNode = n
+	var cleaned string = path.Clean(s.Path)
+	if "." != cleaned {
+		for _, seg := range strings.Split(cleaned, "/") {
+			node = node.child(seg)
+		}
+	}
+
+	switch node.Seen {
+	case true:
+		node.addUp(s.Size-node.Stat.Size, 0)
+	default:
+		node.addUp(s.Size, 1)
+	}
+	node.propagateNewest(s)
+	node.Stat = s
+	node.Seen = true
+}
+
+func (n *DirNode) Path() string {
+	if nil == n.Parent {
+		return "."
+	}
+	var names []string
+	for p := n; nil != p.Parent; p = p.Parent {
+		names = append(names, p.Name)
+	}
+	slices.Reverse(names)
+	return strings.Join(names, "/")
+}
+
+func (n *DirNode) IsDir() bool {
+	return 0 < len(n.Children) || FileTypeFldr == n.Stat.FileType
+}
+
+func (n *DirNode) BySize() []*DirNode {
+	return slices.SortedFunc(maps.Values(n.Children), func(a, b *DirNode) int {
+		return cmp.Or(
+			cmp.Compare(b.Size, a.Size),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+func (n *DirNode) All() iter.Seq[*DirNode] {
+	return func(yield func(*DirNode) bool) {
+		n.walk(yield)
+	}
+}
+
+func (n *DirNode) walk(yield func(*DirNode) bool) bool {
+	if !yield(n) {
+		return false
+	}
+	for _, name := range slices.Sorted(maps.Keys(n.Children)) {
+		if !n.Children[name].walk(yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *DirNode) Stats() BasicStatIter {
+	return func(yield func(BasicStat, error) bool) {
+		for node := range n.All() {
+			if node.Seen && !yield(node.Stat, nil) {
+				return
+			}
+		}
+	}
+}
+
+func BasicStatsToDirTree(stats iter.Seq2[BasicStat, error]) (*DirNode, error) {
+	var root *DirNode = NewDirTree()
+	for s, e := range stats {
+		if nil != e {
+			return root, e
+		}
+		root.Insert(s)
+	}
+	return root, nil
+}
@@ -0,0 +1,79 @@
+package names2stats
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+var tsvEscaper *strings.Replacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"\t", "\\t",
+	"\n", "\\n",
+	"\r", "\\r",
+)
+
+func TsvValue(val any) string { return tsvEscaper.Replace(CsvValue(val)) }
+
+type TsvOptions struct {
+	Columns  []string
+	NoHeader bool
+}
+
+func (o TsvOptions) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+
+		var writeRow func([]string) error = func(row []string) error {
+			_, e := bw.WriteString(strings.Join(row, "\t") + "\n")
+			return e
+		}
+
+		var columns []string = o.Columns
+		var wrote bool
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			if !IsStatRecord(rec) {
+				continue
+			}
+
+			if nil == columns {
+				columns = make([]string, 0, len(rec))
+				for _, f := range rec {
+					columns = append(columns, f.Key)
+				}
+			}
+
+			if !wrote && !o.NoHeader {
+				e = writeRow(columns)
+				if nil != e {
+					return e
+				}
+			}
+			wrote = true
+
+			var row []string = make([]string, 0, len(columns))
+			for _, col := range columns {
+				val, _ := rec.Get(col)
+				row = append(row, TsvValue(val))
+			}
+
+			e = writeRow(row)
+			if nil != e {
+				return e
+			}
+		}
+
+		if !wrote && nil != columns && !o.NoHeader {
+			e := writeRow(columns)
+			if nil != e {
+				return e
+			}
+		}
+		return bw.Flush()
+	}
+}
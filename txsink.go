@@ -0,0 +1,122 @@
+package names2stats
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const IncompleteSuffix string = ".incomplete"
+
+type Transaction interface {
+	Commit() error
+	Abort(cause error) error
+}
+
+type StagedFile struct {
+	Name string
+	file *os.File
+}
+
+func StageFile(name string) (*StagedFile, error) {
+	f, e := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if nil != e {
+		return nil, e
+	}
+	e = f.Chmod(0o644)
+	if nil != e {
+		return nil, errors.Join(e, f.Close(), os.Remove(f.Name()))
+	}
+	return &StagedFile{Name: name, file: f}, nil
+}
+
+func (s *StagedFile) Write(p []byte) (int, error) { return s.file.Write(p) }
+
+func (s *StagedFile) prepare() error {
+	return errors.Join(s.file.Sync(), s.file.Close())
+}
+
+func (s *StagedFile) publish() error {
+	e := os.Rename(s.file.Name(), s.Name)
+	if nil != e {
+		return e
+	}
+	e = os.Remove(s.Name + IncompleteSuffix)
+	if errors.Is(e, fs.ErrNotExist) {
+		return nil
+	}
+	return e
+}
+
+func (s *StagedFile) Commit() error {
+	e := s.prepare()
+	if nil != e {
+		return errors.Join(e, s.Abort(e))
+	}
+	return s.publish()
+}
+
+func (s *StagedFile) Abort(cause error) error {
+	_ = s.file.Close()
+	return errors.Join(
+		os.Remove(s.file.Name()),
+		os.WriteFile(
+			s.Name+IncompleteSuffix,
+			[]byte(fmt.Sprintf("%v\n", cause)),
+			0o644,
+		),
+	)
+}
+
+type StagedFiles []*StagedFile
+
+func StageFiles(names []string) (StagedFiles, error) {
+	var ret StagedFiles
+	for _, name := range names {
+		s, e := StageFile(name)
+		if nil != e {
+			return nil, errors.Join(e, ret.Abort(e))
+		}
+		ret = append(ret, s)
+	}
+	return ret, nil
+}
+
+func (f StagedFiles) Write(p []byte) (int, error) {
+	for _, s := range f {
+		n, e := s.Write(p)
+		if nil != e {
+			return n, fmt.Errorf("%s: %w", s.Name, e)
+		}
+	}
+	return len(p), nil
+}
+
+func (f StagedFiles) Commit() error {
+	for _, s := range f {
+		e := s.prepare()
+		if nil != e {
+			e = fmt.Errorf("%s: %w", s.Name, e)
+			return errors.Join(e, f.Abort(e))
+		}
+	}
+
+	var errs []error
+	for _, s := range f {
+		e := s.publish()
+		if nil != e {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name, e))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f StagedFiles) Abort(cause error) error {
+	var errs []error
+	for _, s := range f {
+		errs = append(errs, s.Abort(cause))
+	}
+	return errors.Join(errs...)
+}
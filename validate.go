@@ -0,0 +1,210 @@
+package names2stats
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidName      error = errors.New("invalid name")
+	ErrUnknownViolation error = errors.New("unknown violation")
+	ErrUnknownAction    error = errors.New("unknown violation action")
+)
+
+type Violation int
+
+const (
+	ViolationNone Violation = iota
+	ViolationEmpty
+	ViolationAbsolute
+	ViolationTraversal
+	ViolationInvalidUtf8
+)
+
+var violationNames map[Violation]string = map[Violation]string{
+	ViolationNone:        "none",
+	ViolationEmpty:       "empty",
+	ViolationAbsolute:    "absolute",
+	ViolationTraversal:   "traversal",
+	ViolationInvalidUtf8: "utf8",
+}
+
+var Violations []Violation = []Violation{
+	ViolationEmpty,
+	ViolationAbsolute,
+	ViolationTraversal,
+	ViolationInvalidUtf8,
+}
+
+func (v Violation) String() string { return violationNames[v] }
+
+func ParseViolation(s string) (Violation, error) {
+	for v, name := range violationNames {
+		if ViolationNone != v && name == s {
+			return v, nil
+		}
+	}
+	return ViolationNone, fmt.Errorf("%w: %s", ErrUnknownViolation, s)
+}
+
+func CheckName(name string) Violation {
+	switch {
+	case "" == name:
+		return ViolationEmpty
+	case !utf8.ValidString(name):
+		return ViolationInvalidUtf8
+	case filepath.IsAbs(name) || strings.HasPrefix(name, "/"):
+		return ViolationAbsolute
+	}
+
+	var segs []string = strings.FieldsFunc(name, func(r rune) bool {
+		return '/' == r || filepath.Separator == r
+	})
+	if slices.Contains(segs, "..") {
+		return ViolationTraversal
+	}
+	return ViolationNone
+}
+
+type ViolationAction int
+
+const (
+	ActionAllow ViolationAction = iota
+	ActionSkip
+	ActionWarn
+	ActionFail
+)
+
+var actionNames map[ViolationAction]string = map[ViolationAction]string{
+	ActionAllow: "allow",
+	ActionSkip:  "skip",
+	ActionWarn:  "warn",
+	ActionFail:  "fail",
+}
+
+func (a ViolationAction) String() string { return actionNames[a] }
+
+func ParseViolationAction(s string) (ViolationAction, error) {
+	for a, name := range actionNames {
+		if name == s {
+			return a, nil
+		}
+	}
+	return ActionAllow, fmt.Errorf("%w: %s", ErrUnknownAction, s)
+}
+
+type ValidationPolicy map[Violation]ViolationAction
+
+func ParseValidationPolicy(s string) (ValidationPolicy, error) {
+	var ret ValidationPolicy = ValidationPolicy{}
+	for _, item := range strings.Split(s, ",") {
+		if "" == item {
+			continue
+		}
+
+		key, val, found := strings.Cut(item, "=")
+		if !found {
+			key, val = "all", item
+		}
+
+		action, e := ParseViolationAction(val)
+		if nil != e {
+			return nil, e
+		}
+
+		if "all" == key {
+			for _, v := range Violations {
+				ret[v] = action
+			}
+			continue
+		}
+
+		v, e := ParseViolation(key)
+		if nil != e {
+			return nil, e
+		}
+		ret[v] = action
+	}
+	return ret, nil
+}
+
+type NameValidator struct {
+	Policy ValidationPolicy
+	Warn   func(TaggedName, Violation)
+
+	mu     sync.Mutex
+	counts map[Violation]int
+}
+
+func (v *NameValidator) count(violation Violation) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if nil == v.counts {
+		v.counts = map[Violation]int{}
+	}
+	v.counts[violation]++
+}
+
+func (v *NameValidator) Counts() map[Violation]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return maps.Clone(v.counts)
+}
+
+func (v *NameValidator) Summary() string {
+	var counts map[Violation]int = v.Counts()
+	var parts []string = make([]string, 0, len(Violations))
+	for _, violation := range Violations {
+		parts = append(parts, fmt.Sprintf(
+			"%s=%d(%s)",
+			violation,
+			counts[violation],
+			v.Policy[violation],
+		))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (v *NameValidator) Validate(names TaggedNameIter) TaggedNameIter {
+	return func(yield func(TaggedName, error) bool) {
+		for tagged, e := range names {
+			if nil != e {
+				yield(tagged, e)
+				return
+			}
+
+			var violation Violation = CheckName(tagged.Name)
+			if ViolationNone != violation {
+				v.count(violation)
+			}
+
+			switch v.Policy[violation] {
+			case ActionSkip:
+				continue
+			case ActionWarn:
+				if nil != v.Warn {
+					v.Warn(tagged, violation)
+				}
+			case ActionFail:
+				yield(tagged, tagged.Origin.WrapErr(fmt.Errorf(
+					"%w(%s): %q",
+					ErrInvalidName,
+					violation,
+					tagged.Name,
+				)))
+				return
+			}
+
+			if !yield(tagged, nil) {
+				return
+			}
+		}
+	}
+}
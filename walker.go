@@ -0,0 +1,254 @@
+package names2stats
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"sync"
+)
+
+var ErrWalkStopped error = errors.New("walk stopped")
+
+type Walker struct {
+	OnStat     func(BasicStat) error
+	OnError    func(name string, e error) error
+	OnDirEnter func(BasicStat) error
+	OnDirExit  func(BasicStat) error
+
+	Prune       func(name string, d fs.DirEntry) bool
+	MaxDepth    int
+	Concurrency int
+	InodeOrder  bool
+	TypesOnly   bool
+
+	Stat FilenameToBasicStat
+
+	readDir func(dir string) ([]fs.DirEntry, error)
+}
+
+type walkResult struct {
+	stat BasicStat
+	err  error
+}
+
+func (w Walker) onError(name string, e error) error {
+	if nil == w.OnError {
+		return e
+	}
+	return w.OnError(name, e)
+}
+
+type inoder interface{ Inode() uint64 }
+
+func inodeOf(ent fs.DirEntry) uint64 {
+	i, ok := ent.(inoder)
+	if !ok {
+		return 0
+	}
+	return i.Inode()
+}
+
+func (w Walker) statOrder(entries []fs.DirEntry) []int {
+	var order []int = make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	if !w.InodeOrder {
+		return order
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(inodeOf(entries[a]), inodeOf(entries[b]))
+	})
+	return order
+}
+
+func (w Walker) statAll(ctx context.Context, names []string, order []int) []walkResult {
+	var results []walkResult = make([]walkResult, len(names))
+	if w.Concurrency <= 1 {
+		for _, i := range order {
+			s, e := w.Stat(names[i])
+			results[i] = walkResult{stat: s, err: e}
+		}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	var sem chan struct{} = make(chan struct{}, w.Concurrency)
+	for _, i := range order {
+		var name string = names[i]
+		if nil != ctx.Err() {
+			results[i] = walkResult{err: ctx.Err()}
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			s, e := w.Stat(name)
+			results[i] = walkResult{stat: s, err: e}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (w Walker) classifyAll(names []string, entries []fs.DirEntry) []walkResult {
+	var results []walkResult = make([]walkResult, len(names))
+	for i, ent := range entries {
+		var t FileType = FileMode{ent.Type()}.ToFileType()
+		switch t {
+		case FileTypeUnspecified, FileTypeSyml:
+			s, e := w.Stat(names[i])
+			results[i] = walkResult{stat: s, err: e}
+		default:
+			results[i] = walkResult{stat: BasicStat{Path: names[i], FileType: t}}
+		}
+	}
+	return results
+}
+
+func (w Walker) resultsOf(
+	ctx context.Context,
+	names []string,
+	entries []fs.DirEntry,
+) []walkResult {
+	switch w.TypesOnly {
+	case true:
+		return w.classifyAll(names, entries)
+	default:
+		return w.statAll(ctx, names, w.statOrder(entries))
+	}
+}
+
+func (w Walker) readDirOf(fsys fs.FS, dir string) ([]fs.DirEntry, error) {
+	if nil == w.readDir {
+		return fs.ReadDir(fsys, dir)
+	}
+	return w.readDir(dir)
+}
+
+func call(f func(BasicStat) error, s BasicStat) error {
+	if nil == f {
+		return nil
+	}
+	return f(s)
+}
+
+func (w Walker) walkDir(
+	ctx context.Context,
+	fsys fs.FS,
+	dir string,
+	depth int,
+) error {
+	entries, e := w.readDirOf(fsys, dir)
+	if nil != e {
+		return w.onError(dir, e)
+	}
+
+	var names []string = make([]string, 0, len(entries))
+	var kept []fs.DirEntry = make([]fs.DirEntry, 0, len(entries))
+	for _, ent := range entries {
+		var name string = path.Join(dir, ent.Name())
+		if nil != w.Prune && w.Prune(name, ent) {
+			continue
+		}
+		names = append(names, name)
+		kept = append(kept, ent)
+	}
+
+	for i, res := range w.resultsOf(ctx, names, kept) {
+		if nil != ctx.Err() {
+			return ctx.Err()
+		}
+
+		if nil != res.err {
+			e = w.onError(names[i], res.err)
+			if nil != e {
+				return e
+			}
+			continue
+		}
+
+		e = call(w.OnStat, res.stat)
+		if nil != e {
+			return e
+		}
+
+		if !kept[i].IsDir() {
+			continue
+		}
+		if 0 < w.MaxDepth && w.MaxDepth <= depth {
+			continue
+		}
+
+		e = call(w.OnDirEnter, res.stat)
+		if errors.Is(e, fs.SkipDir) {
+			continue
+		}
+		if nil != e {
+			return e
+		}
+
+		e = w.walkDir(ctx, fsys, names[i], depth+1)
+		if nil != e {
+			return e
+		}
+
+		e = call(w.OnDirExit, res.stat)
+		if nil != e {
+			return e
+		}
+	}
+	return nil
+}
+
+func (w Walker) Walk(ctx context.Context, r Root, start string) error {
+	if nil == w.Stat {
+		w.Stat = r.ToFilenameToBasicStat()
+	}
+	if w.InodeOrder {
+		w.readDir = r.ReadDirInodes
+	}
+
+	var dir string = path.Clean(start)
+	e := w.walkDir(ctx, r.ToFS(), dir, 1)
+	if errors.Is(e, ErrWalkStopped) || errors.Is(e, fs.SkipAll) {
+		return nil
+	}
+	return e
+}
+
+func (w Walker) ToBasicStats(
+	ctx context.Context,
+	r Root,
+	start string,
+) BasicStatIter {
+	return func(yield func(BasicStat, error) bool) {
+		var stopped bool
+		var walker Walker = w
+		walker.OnStat = func(s BasicStat) error {
+			e := call(w.OnStat, s)
+			if nil != e {
+				return e
+			}
+			if !yield(s, nil) {
+				stopped = true
+				return ErrWalkStopped
+			}
+			return nil
+		}
+
+		e := walker.Walk(ctx, r, start)
+		if nil != e && !stopped {
+			var empty BasicStat
+			yield(empty, e)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package names2stats
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func benchTree(b *testing.B, dirs int, files int) string {
+	var root string = b.TempDir()
+	for d := range dirs {
+		var dir string = filepath.Join(root, "d"+strconv.Itoa(d))
+		e := os.Mkdir(dir, 0o755)
+		if nil != e {
+			b.Fatal(e)
+		}
+		for f := range files {
+			e = os.WriteFile(filepath.Join(dir, "f"+strconv.Itoa(f)), nil, 0o644)
+			if nil != e {
+				b.Fatal(e)
+			}
+		}
+	}
+	return root
+}
+
+func BenchmarkWalkerInodeOrder(b *testing.B) {
+	var root string = benchTree(b, 16, 256)
+
+	for _, inodeOrder := range []bool{false, true} {
+		b.Run("inode="+strconv.FormatBool(inodeOrder), func(b *testing.B) {
+			b.ReportAllocs()
+			e := RootDirname(root).WithRoot(func(r Root) error {
+				var w Walker = Walker{InodeOrder: inodeOrder}
+				for b.Loop() {
+					e := w.Walk(context.Background(), r, ".")
+					if nil != e {
+						return e
+					}
+				}
+				return nil
+			})
+			if nil != e {
+				b.Fatal(e)
+			}
+		})
+	}
+}
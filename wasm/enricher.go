@@ -0,0 +1,150 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	ns "github.com/takanoriyanagitani/go-names2stats"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+const (
+	ExportAlloc  string = "alloc"
+	ExportEnrich string = "enrich"
+	ExportInit   string = "_initialize"
+	ExportMemory string = "memory"
+)
+
+var (
+	ErrMissingExport error = errors.New("wasm export missing")
+	ErrOutOfRange    error = errors.New("wasm memory access out of range")
+)
+
+type Enricher struct {
+	ns.FileTypeToString
+
+	mu      sync.Mutex
+	runtime wazero.Runtime
+	memory  api.Memory
+	alloc   api.Function
+	enrich  api.Function
+}
+
+func NewEnricher(ctx context.Context, wasmBytes []byte) (*Enricher, error) {
+	var rtm wazero.Runtime = wazero.NewRuntime(ctx)
+
+	_, e := wasi_snapshot_preview1.Instantiate(ctx, rtm)
+	if nil != e {
+		return nil, errors.Join(e, rtm.Close(ctx))
+	}
+
+	compiled, e := rtm.CompileModule(ctx, wasmBytes)
+	if nil != e {
+		return nil, errors.Join(e, rtm.Close(ctx))
+	}
+
+	var cfg wazero.ModuleConfig = wazero.NewModuleConfig().
+		WithStartFunctions().
+		WithStderr(os.Stderr)
+	_, reactor := compiled.ExportedFunctions()[ExportInit]
+	if reactor {
+		cfg = cfg.WithStartFunctions(ExportInit)
+	}
+
+	mdl, e := rtm.InstantiateModule(ctx, compiled, cfg)
+	if nil != e {
+		return nil, errors.Join(e, rtm.Close(ctx))
+	}
+
+	var enricher *Enricher = &Enricher{
+		FileTypeToString: ns.FileTypeToStringDefault,
+		runtime:          rtm,
+		memory:           mdl.ExportedMemory(ExportMemory),
+		alloc:            mdl.ExportedFunction(ExportAlloc),
+		enrich:           mdl.ExportedFunction(ExportEnrich),
+	}
+
+	for name, f := range map[string]api.Function{
+		ExportAlloc:  enricher.alloc,
+		ExportEnrich: enricher.enrich,
+	} {
+		if nil == f {
+			return nil, errors.Join(
+				fmt.Errorf("%w: %s", ErrMissingExport, name),
+				rtm.Close(ctx),
+			)
+		}
+	}
+	if nil == enricher.memory {
+		return nil, errors.Join(
+			fmt.Errorf("%w: %s", ErrMissingExport, ExportMemory),
+			rtm.Close(ctx),
+		)
+	}
+
+	return enricher, nil
+}
+
+func NewEnricherFromFile(ctx context.Context, filename string) (*Enricher, error) {
+	wasmBytes, e := os.ReadFile(filename)
+	if nil != e {
+		return nil, e
+	}
+	return NewEnricher(ctx, wasmBytes)
+}
+
+func (w *Enricher) Enrich(
+	ctx context.Context,
+	_ ns.Root,
+	stat ns.BasicStat,
+) (ns.EnrichedStat, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var ret ns.EnrichedStat = ns.EnrichedStat{BasicStat: stat}
+
+	input, e := json.Marshal(stat.ToJsonObj(w.FileTypeToString))
+	if nil != e {
+		return ret, e
+	}
+
+	allocated, e := w.alloc.Call(ctx, uint64(len(input)))
+	if nil != e {
+		return ret, e
+	}
+
+	var ptr uint32 = uint32(allocated[0])
+	var mem api.Memory = w.memory
+	if !mem.Write(ptr, input) {
+		return ret, ErrOutOfRange
+	}
+
+	packed, e := w.enrich.Call(ctx, uint64(ptr), uint64(len(input)))
+	if nil != e {
+		return ret, e
+	}
+
+	var optr uint32 = uint32(packed[0] >> 32)
+	var olen uint32 = uint32(packed[0])
+	if 0 == olen {
+		return ret, nil
+	}
+
+	output, ok := mem.Read(optr, olen)
+	if !ok {
+		return ret, ErrOutOfRange
+	}
+
+	e = json.Unmarshal(output, &ret.Extra)
+	return ret, e
+}
+
+func (w *Enricher) Close() error {
+	return w.runtime.Close(context.Background())
+}
@@ -0,0 +1,133 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+)
+
+const (
+	XmlRootDefault    string = "stats"
+	XmlElementDefault string = "stat"
+)
+
+var ErrInvalidXmlName error = errors.New("invalid xml element name")
+
+var xmlName *regexp.Regexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9._-]*$`)
+
+func ParseXmlName(s string) (string, error) {
+	if !xmlName.MatchString(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidXmlName, s)
+	}
+	return s, nil
+}
+
+type XmlOptions struct {
+	Root    string
+	Element string
+}
+
+func (o XmlOptions) names() (root string, elem string) {
+	root, elem = o.Root, o.Element
+	if "" == root {
+		root = XmlRootDefault
+	}
+	if "" == elem {
+		elem = XmlElementDefault
+	}
+	return root, elem
+}
+
+func xmlText(buf *bytes.Buffer, v any) error {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return xml.EscapeText(buf, []byte(t))
+	case time.Time:
+		buf.WriteString(t.Format(time.RFC3339Nano))
+		return nil
+	default:
+		raw, e := json.Marshal(t)
+		if nil != e {
+			return e
+		}
+		var s string
+		if nil == json.Unmarshal(raw, &s) {
+			return xml.EscapeText(buf, []byte(s))
+		}
+		return xml.EscapeText(buf, raw)
+	}
+}
+
+func appendXmlFields(buf *bytes.Buffer, r Record) error {
+	for _, f := range r {
+		var open, end string = "<" + f.Key + ">", "</" + f.Key + ">"
+		if !xmlName.MatchString(f.Key) {
+			var name bytes.Buffer
+			e := xml.EscapeText(&name, []byte(f.Key))
+			if nil != e {
+				return e
+			}
+			open, end = `<field name="`+name.String()+`">`, "</field>"
+		}
+
+		buf.WriteString(open)
+		var e error
+		nested, ok := f.Value.(Record)
+		switch ok {
+		case true:
+			e = appendXmlFields(buf, nested)
+		default:
+			e = xmlText(buf, f.Value)
+		}
+		if nil != e {
+			return e
+		}
+		buf.WriteString(end)
+	}
+	return nil
+}
+
+func (o XmlOptions) RecordsToWriter(wtr io.Writer) func(RecordIter) error {
+	var root, elem string = o.names()
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		_, e := bw.WriteString(xml.Header + "<" + root + ">\n")
+		if nil != e {
+			return e
+		}
+
+		var buf bytes.Buffer
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			buf.Reset()
+			buf.WriteString("<" + elem + ">")
+			e = appendXmlFields(&buf, rec)
+			if nil != e {
+				return e
+			}
+			buf.WriteString("</" + elem + ">\n")
+
+			_, e = bw.Write(buf.Bytes())
+			if nil != e {
+				return e
+			}
+		}
+
+		_, e = bw.WriteString("</" + root + ">\n")
+		if nil != e {
+			return e
+		}
+		return bw.Flush()
+	}
+}
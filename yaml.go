@@ -0,0 +1,83 @@
+package names2stats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"regexp"
+)
+
+var yamlPlainKey *regexp.Regexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func appendYamlScalar(buf []byte, v any) ([]byte, error) {
+	var b bytes.Buffer
+	var enc *json.Encoder = json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+
+	var e error
+	nested, ok := v.(Record)
+	switch ok {
+	case true:
+		e = nested.appendJSON(&b, false)
+	default:
+		e = encodeJSON(&b, enc, v)
+	}
+	if nil != e {
+		return buf, e
+	}
+	return append(buf, b.Bytes()...), nil
+}
+
+func AppendYamlDocument(buf []byte, r Record) ([]byte, error) {
+	buf = append(buf, "---"...)
+	if 0 == len(r) {
+		return append(buf, " {}\n"...), nil
+	}
+	buf = append(buf, '\n')
+
+	for _, f := range r {
+		var e error
+		switch yamlPlainKey.MatchString(f.Key) {
+		case true:
+			buf = append(buf, f.Key...)
+		default:
+			buf, e = appendYamlScalar(buf, f.Key)
+			if nil != e {
+				return buf, e
+			}
+		}
+		buf = append(buf, ": "...)
+
+		buf, e = appendYamlScalar(buf, f.Value)
+		if nil != e {
+			return buf, e
+		}
+		buf = append(buf, '\n')
+	}
+	return buf, nil
+}
+
+func RecordsToYamlWriter(wtr io.Writer) func(RecordIter) error {
+	return func(records RecordIter) error {
+		var bw *bufio.Writer = bufio.NewWriter(wtr)
+		var buf []byte
+		for rec, e := range records {
+			if nil != e {
+				return errors.Join(e, bw.Flush())
+			}
+
+			buf, e = AppendYamlDocument(buf[:0], rec)
+			if nil != e {
+				return e
+			}
+
+			_, e = bw.Write(buf)
+			if nil != e {
+				return e
+			}
+		}
+		return bw.Flush()
+	}
+}